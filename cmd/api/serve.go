@@ -18,7 +18,6 @@ var ServeCmd = &cobra.Command{
 			healthcheck.RunServer(),
 			httpserver.RunServer(),
 			mcpserver.RunStreamableHTTPServer(),
-			//worker.RunWorkers(),
 		)
 	},
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-oryn/oryn-sandbox/internal"
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect config",
+}
+
+var ShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Show effective config",
+	Example: strings.Join(showExamples, "\n"),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, _ := cmd.Flags().GetString("env")
+		key, _ := cmd.Flags().GetString("key")
+		profiles, _ := cmd.Flags().GetStringSlice("profile")
+
+		var options []fx.Option
+		if env != "" {
+			options = append(options, config.AsConfigOptions(config.WithEnvironment(env)))
+		}
+		if len(profiles) > 0 {
+			options = append(options, config.AsConfigOptions(config.WithProfiles(profiles...)))
+		}
+
+		// only the config is needed, without booting the app modules and their resources like the db connections
+		app := fx.New(
+			fx.NopLogger,
+			config.Module,
+			internal.Config,
+			fx.Options(options...),
+			fx.Invoke(func(cfg *config.Config) error {
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+				fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+				for _, setting := range cfg.Settings(key) {
+					fmt.Fprintf(w, "%s\t%v\t%s\n", setting.Key, setting.Value, setting.Source)
+				}
+
+				return w.Flush()
+			}),
+		)
+		if err := app.Err(); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var showExamples = []string{
+	"  config show                    # show the effective config of the current environment",
+	"  config show --env prod         # show the effective config of the prod environment",
+	"  config show --env prod,prod-eu # show the effective config of the prod then prod-eu environments",
+	"  config show --profile canary   # show the effective config with the canary profile",
+	"  config show --key db           # show the effective config under the db key",
+}
+
+func init() {
+	ShowCmd.Flags().String("env", "", "config environment (defaults to ORYN_ENV)")
+	ShowCmd.Flags().StringSlice("profile", nil, "config profiles to layer on top of the environment")
+	ShowCmd.Flags().String("key", "", "config key prefix to show")
+
+	ConfigCmd.AddCommand(ShowCmd)
+}
@@ -5,14 +5,18 @@ import (
 	"os"
 
 	"github.com/go-oryn/oryn-sandbox/cmd/api"
+	"github.com/go-oryn/oryn-sandbox/cmd/config"
 	"github.com/go-oryn/oryn-sandbox/cmd/db"
+	"github.com/go-oryn/oryn-sandbox/cmd/worker"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	RootCmd.AddCommand(api.ServeCmd)
+	RootCmd.AddCommand(config.ConfigCmd)
 	RootCmd.AddCommand(db.MigrateCmd)
 	RootCmd.AddCommand(db.SeedCmd)
+	RootCmd.AddCommand(worker.WorkCmd)
 
 }
 
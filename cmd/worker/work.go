@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"strings"
+
+	"github.com/go-oryn/oryn-sandbox/internal"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"github.com/spf13/cobra"
+)
+
+var WorkCmd = &cobra.Command{
+	Use:     "work [worker names...]",
+	Short:   "Run workers",
+	Example: strings.Join(workExamples, "\n"),
+	Run: func(cmd *cobra.Command, args []string) {
+		internal.Run(
+			cmd.Context(),
+			//fx.NopLogger,
+			healthcheck.RunDedicatedServer(),
+			worker.RunWorkers(args...),
+		)
+	},
+}
+
+var workExamples = []string{
+	"  work           # run all workers",
+	"  work foo bar   # run foo and bar workers only",
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE worker_leases (
+    name       VARCHAR(255) NOT NULL PRIMARY KEY,
+    holder     VARCHAR(255) NOT NULL,
+    expires_at DATETIME(6) NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS worker_leases;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE queue_jobs (
+    id            BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    type          VARCHAR(255) NOT NULL,
+    payload       JSON NOT NULL,
+    status        VARCHAR(32) NOT NULL,
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    max_attempts  INTEGER NOT NULL,
+    last_error    TEXT,
+    trace_context JSON,
+    run_at        DATETIME(6) NOT NULL,
+    locked_until  DATETIME(6),
+    created_at    DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at    DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+    INDEX queue_jobs_claim_idx (type, status, run_at)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS queue_jobs;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE outbox_events (
+    id              BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    topic           VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    payload         JSON NOT NULL,
+    headers         JSON,
+    status          VARCHAR(32) NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    created_at      DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    delivered_at    DATETIME(6),
+    UNIQUE INDEX outbox_events_idempotency_key_idx (idempotency_key),
+    INDEX outbox_events_status_idx (status, id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX outbox_events_topic_idx ON outbox_events (topic, status, id);
+
+-- +goose Down
+DROP INDEX outbox_events_topic_idx ON outbox_events;
@@ -3,6 +3,7 @@ package seeds
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sort"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
@@ -23,13 +24,14 @@ func (s *UsersSeed) Name() string {
 }
 
 func (s *UsersSeed) Run(ctx context.Context, db *sql.DB) error {
-	var txErr error
-
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	// no-op once committed
+	defer tx.Rollback()
+
 	seedData := s.config.GetStringMapString("db.seeds.users")
 
 	names := make([]string, 0, len(seedData))
@@ -40,11 +42,10 @@ func (s *UsersSeed) Run(ctx context.Context, db *sql.DB) error {
 	sort.Strings(names)
 
 	for _, name := range names {
-		_, txErr = tx.ExecContext(ctx, "INSERT INTO users (name, job) VALUES (?, ?)", name, seedData[name])
-	}
-
-	if txErr != nil {
-		return tx.Rollback()
+		_, err = tx.ExecContext(ctx, "INSERT INTO users (name, job) VALUES (?, ?)", name, seedData[name])
+		if err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", name, err)
+		}
 	}
 
 	return tx.Commit()
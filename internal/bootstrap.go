@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"cmp"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-oryn/oryn-sandbox/configs"
@@ -14,22 +17,51 @@ import (
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
 	"github.com/go-oryn/oryn-sandbox/pkg/core"
 	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/featureflag"
 	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck/probes"
 	"github.com/go-oryn/oryn-sandbox/pkg/httpclient"
 	"github.com/go-oryn/oryn-sandbox/pkg/httpserver"
 	"github.com/go-oryn/oryn-sandbox/pkg/mcpserver"
+	"github.com/go-oryn/oryn-sandbox/pkg/outbox"
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+	"github.com/go-oryn/oryn-sandbox/pkg/scheduler"
 	"github.com/go-oryn/oryn-sandbox/pkg/worker"
 
 	"go.uber.org/fx"
 )
 
+// configDir is the external config directory, like a mounted Kubernetes ConfigMap.
+var configDir = cmp.Or(os.Getenv("ORYN_CONFIG_DIR"), "/etc/oryn")
+
+// Config provides the app config sources and secret providers, for the config.Module.
+var Config = fx.Options(
+	// app config
+	config.AsConfigOptions(
+		config.WithEmbedFS(configs.ConfigFS),
+		config.WithDirectory(configDir),
+	),
+	// app config secrets
+	config.AsSecretProvider(func() *config.FileSecretProvider {
+		return config.NewFileSecretProvider("/run/secrets")
+	}),
+	config.AsSecretProvider(func() *config.EncryptedFileSecretProvider {
+		return config.NewEncryptedFileSecretProvider(filepath.Join(configDir, "secrets"), "ORYN_SECRETS_KEY")
+	}),
+)
+
 var Bootstrapper = core.NewBootstrapper(
 	// shared modules
 	db.Module,
+	featureflag.Module,
 	healthcheck.Module,
+	probes.Module,
 	httpclient.Module,
 	httpserver.Module,
 	mcpserver.Module,
+	outbox.Module,
+	queue.Module,
+	scheduler.Module,
 	worker.Module,
 	// app modules
 	internalapi.Module,
@@ -38,9 +70,11 @@ var Bootstrapper = core.NewBootstrapper(
 	internalinfra.Module,
 	internalworker.Module,
 	// app config
-	config.AsConfigOptions(config.WithEmbedFS(configs.ConfigFS)),
+	Config,
 	// app migrations
 	db.AsMigratorOptions(db.WithMigrationsEmbedFS(migrations.MigrationsFS)),
+	// app singleton workers leases
+	worker.AsLeaseStore(worker.ProvideSQLLeaseStore),
 )
 
 func Run(ctx context.Context, options ...fx.Option) {
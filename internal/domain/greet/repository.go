@@ -2,22 +2,23 @@ package greet
 
 import (
 	"context"
-	"database/sql"
 	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
 )
 
 type Repository struct {
-	db *sql.DB
+	transactor *db.Transactor
 }
 
-func NewRepository(db *sql.DB) *Repository {
+func NewRepository(transactor *db.Transactor) *Repository {
 	return &Repository{
-		db: db,
+		transactor: transactor,
 	}
 }
 
 func (r *Repository) Time(ctx context.Context) (time.Time, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT CURRENT_TIMESTAMP`)
+	rows, err := r.transactor.Querier(ctx).QueryContext(ctx, `SELECT CURRENT_TIMESTAMP`)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/featureflag"
 	"github.com/go-oryn/oryn-sandbox/pkg/otel"
 	"go.opentelemetry.io/otel/metric"
 )
@@ -15,11 +16,18 @@ type Service struct {
 	client    *http.Client
 	repo      *Repository
 	config    *config.Config
+	flags     *featureflag.Flags
 	telemetry otel.Telemetry
 	counter   metric.Int64Counter
 }
 
-func NewService(client *http.Client, repo *Repository, config *config.Config, telemetry otel.Telemetry) (*Service, error) {
+func NewService(
+	client *http.Client,
+	repo *Repository,
+	config *config.Config,
+	flags *featureflag.Flags,
+	telemetry otel.Telemetry,
+) (*Service, error) {
 	counter, err := telemetry.Meter().Int64Counter(
 		"greet.counter",
 		metric.WithDescription("Number of greets."),
@@ -33,6 +41,7 @@ func NewService(client *http.Client, repo *Repository, config *config.Config, te
 		client:    client,
 		repo:      repo,
 		config:    config,
+		flags:     flags,
 		telemetry: telemetry,
 		counter:   counter,
 	}, nil
@@ -48,12 +57,12 @@ func (s *Service) Greet(ctx context.Context) string {
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.google.com", nil)
 	if err != nil {
-		s.telemetry.Logger().ErrorContext(ctx, "cannot prepare http request", err)
+		s.telemetry.Logger().ErrorContext(ctx, "cannot prepare http request", "error", err)
 	}
 
 	res, err := s.client.Do(req)
 	if err != nil {
-		s.telemetry.Logger().ErrorContext(ctx, "cannot send http request", err)
+		s.telemetry.Logger().ErrorContext(ctx, "cannot send http request", "error", err)
 	}
 	defer res.Body.Close()
 
@@ -67,6 +76,14 @@ func (s *Service) Greet(ctx context.Context) string {
 		)
 	}
 
+	if s.flags.Enabled(ctx, "new-greeting") {
+		return fmt.Sprintf(
+			"Greetings from %s, it is now %s on the db, have a nice day!",
+			s.config.GetString("app.name"),
+			dbTime.Format(time.RFC3339),
+		)
+	}
+
 	return fmt.Sprintf(
 		"Greetings from %s, it is now %s on the db.",
 		s.config.GetString("app.name"),
@@ -0,0 +1,43 @@
+package infra
+
+import (
+	"net/http"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/go-oryn/oryn-sandbox/pkg/httpserver"
+	"github.com/go-oryn/oryn-sandbox/pkg/mcpserver"
+	"github.com/labstack/echo/v4"
+)
+
+// ProvideHealthCheckRoutes mounts the health check routes on the httpserver with the main mount, excluded from its tracing.
+func ProvideHealthCheckRoutes(server *healthcheck.Server) []httpserver.Route {
+	paths := server.MainPaths(false)
+
+	routes := make([]httpserver.Route, 0, len(paths))
+	for _, path := range paths {
+		routes = append(routes, httpserver.Route{
+			Method:   http.MethodGet,
+			Path:     path,
+			Handler:  echo.WrapHandler(server.HTTPServer()),
+			Untraced: true,
+		})
+	}
+
+	return routes
+}
+
+// ProvideHealthCheckMCPHandlers mounts the health check routes on the MCP streamable HTTP server with the main mount,
+// if enabled.
+func ProvideHealthCheckMCPHandlers(server *healthcheck.Server) []mcpserver.HTTPHandler {
+	paths := server.MainPaths(true)
+
+	handlers := make([]mcpserver.HTTPHandler, 0, len(paths))
+	for _, path := range paths {
+		handlers = append(handlers, mcpserver.HTTPHandler{
+			Pattern: http.MethodGet + " " + path,
+			Handler: server.HTTPServer(),
+		})
+	}
+
+	return handlers
+}
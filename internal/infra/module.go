@@ -4,6 +4,8 @@ import (
 	"github.com/go-oryn/oryn-sandbox/db/seeds"
 	"github.com/go-oryn/oryn-sandbox/pkg/db"
 	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/go-oryn/oryn-sandbox/pkg/httpserver"
+	"github.com/go-oryn/oryn-sandbox/pkg/mcpserver"
 	"github.com/go-oryn/oryn-sandbox/pkg/worker"
 	"go.uber.org/fx"
 )
@@ -15,6 +17,9 @@ var Module = fx.Module(
 	// db seeders
 	db.AsSeeds(seeds.NewUsersSeed),
 	// health check probes
-	healthcheck.AsProbe(db.NewDBProbe),
-	healthcheck.AsProbe(worker.NewWorkersProbe),
+	healthcheck.AsProbe(db.NewDBProbe, healthcheck.Readiness, healthcheck.Startup),
+	healthcheck.AsProbe(worker.NewWorkersProbe, healthcheck.Liveness),
+	// health check routes, with the main mount
+	httpserver.AsRoutes(ProvideHealthCheckRoutes),
+	mcpserver.AsHTTPHandlers(ProvideHealthCheckMCPHandlers),
 )
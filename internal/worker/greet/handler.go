@@ -0,0 +1,32 @@
+package greet
+
+import (
+	"context"
+
+	"github.com/go-oryn/oryn-sandbox/internal/domain/greet"
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+)
+
+const GreetJobType = "greet"
+
+type GreetPayload struct {
+	From string `json:"from"`
+}
+
+var _ queue.Handler[GreetPayload] = (*GreetHandler)(nil)
+
+type GreetHandler struct {
+	service *greet.Service
+}
+
+func NewGreetHandler(service *greet.Service) *GreetHandler {
+	return &GreetHandler{
+		service: service,
+	}
+}
+
+func (h *GreetHandler) Handle(ctx context.Context, payload GreetPayload) error {
+	h.service.Greet(ctx)
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package greet
+
+import (
+	"context"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+	"github.com/go-oryn/oryn-sandbox/pkg/scheduler"
+)
+
+var _ scheduler.Job = (*GreetJob)(nil)
+
+// GreetJob enqueues a greet job on each run, processed by the GreetHandler.
+type GreetJob struct {
+	queue *queue.Queue
+}
+
+func NewGreetJob(queue *queue.Queue) *GreetJob {
+	return &GreetJob{
+		queue: queue,
+	}
+}
+
+func (j *GreetJob) Name() string {
+	return "greet-enqueue"
+}
+
+func (j *GreetJob) Run(ctx context.Context) error {
+	_, err := j.queue.Enqueue(ctx, GreetJobType, GreetPayload{From: j.Name()})
+
+	return err
+}
@@ -2,6 +2,8 @@ package worker
 
 import (
 	"github.com/go-oryn/oryn-sandbox/internal/worker/greet"
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+	"github.com/go-oryn/oryn-sandbox/pkg/scheduler"
 	"github.com/go-oryn/oryn-sandbox/pkg/worker"
 	"go.uber.org/fx"
 )
@@ -12,4 +14,6 @@ var Module = fx.Module(
 	ModuleName,
 	// greet
 	worker.AsWorker(greet.NewGreetWorker),
+	scheduler.AsJob(greet.NewGreetJob),
+	queue.AsHandler[greet.GreetPayload](greet.GreetJobType, greet.NewGreetHandler),
 )
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-viper/mapstructure/v2"
+)
+
+var bindValidator = newBindValidator()
+
+// Bind decodes a config section into a T struct.
+// The fields are matched with their `mapstructure` tag, initialised from their `default` tag,
+// and checked against their `validate` tag. Unknown keys in the section are reported as errors.
+func Bind[T any](cfg *Config, section string) (T, error) {
+	var out T
+
+	t := reflect.TypeOf(out)
+	if t == nil || t.Kind() != reflect.Struct {
+		return out, fmt.Errorf("cannot bind config section %s: %T is not a struct", section, out)
+	}
+
+	// Apply defaults
+	if err := decode(defaults(t), &out, nil); err != nil {
+		return out, fmt.Errorf("cannot apply config section %s defaults: %w", section, err)
+	}
+
+	// Decode the section
+	var metadata mapstructure.Metadata
+	if err := decode(sectionValues(cfg, section), &out, &metadata); err != nil {
+		return out, fmt.Errorf("cannot decode config section %s: %w", section, err)
+	}
+
+	var errs []error
+
+	slices.Sort(metadata.Unused)
+	for _, key := range metadata.Unused {
+		errs = append(errs, fmt.Errorf("%s.%s: unknown key", section, key))
+	}
+
+	// Validate the section
+	if err := bindValidator.Struct(out); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return out, fmt.Errorf("cannot validate config section %s: %w", section, err)
+		}
+
+		for _, validationErr := range validationErrs {
+			// strip the root struct name from the namespace
+			_, key, _ := strings.Cut(validationErr.Namespace(), ".")
+
+			errs = append(errs, fmt.Errorf(
+				"%s.%s: invalid value %v, failed on %s validation",
+				section,
+				key,
+				validationErr.Value(),
+				validationErr.Tag(),
+			))
+		}
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// sectionValues returns the nested values of a section, built from the leaf keys so environment variables overrides are included.
+func sectionValues(cfg *Config, section string) map[string]any {
+	values := map[string]any{}
+	prefix := strings.ToLower(section) + "."
+
+	for _, key := range cfg.AllKeys() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		current := values
+		path := strings.Split(strings.TrimPrefix(key, prefix), ".")
+
+		for _, part := range path[:len(path)-1] {
+			next, ok := current[part].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				current[part] = next
+			}
+
+			current = next
+		}
+
+		current[path[len(path)-1]] = cfg.Get(key)
+	}
+
+	return values
+}
+
+func decode(input map[string]any, output any, metadata *mapstructure.Metadata) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		Metadata:         metadata,
+		Result:           output,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// defaults returns the nested values of the `default` tags of a struct type.
+func defaults(t reflect.Type) map[string]any {
+	values := map[string]any{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+
+		if value, ok := field.Tag.Lookup("default"); ok {
+			values[name] = value
+
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			if nested := defaults(field.Type); len(nested) > 0 {
+				values[name] = nested
+			}
+		}
+	}
+
+	return values
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+func newBindValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// report the config keys instead of the struct field names
+	v.RegisterTagNameFunc(fieldName)
+
+	return v
+}
@@ -0,0 +1,68 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSettings struct {
+	Address string        `mapstructure:"address" default:":8080" validate:"required"`
+	Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+	Level   string        `mapstructure:"level" default:"info" validate:"oneof=debug info"`
+	TLS     struct {
+		Enabled bool `mapstructure:"enabled" default:"true"`
+	} `mapstructure:"tls"`
+}
+
+func TestBind(t *testing.T) {
+	t.Setenv("ORYN_SERVER_TIMEOUT", "10s")
+
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"server": map[string]any{
+			"address": ":9090",
+			"timeout": "1s",
+		},
+	}))
+	require.NoError(t, err)
+
+	settings, err := config.Bind[testSettings](cfg, "server")
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9090", settings.Address)
+	assert.Equal(t, 1*time.Second, settings.Timeout)
+	assert.Equal(t, "info", settings.Level)
+	assert.True(t, settings.TLS.Enabled)
+}
+
+func TestBindErrors(t *testing.T) {
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"server": map[string]any{
+			"adress": ":9090",
+			"level":  "trace",
+		},
+	}))
+	require.NoError(t, err)
+
+	_, err = config.Bind[testSettings](cfg, "server")
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "server.adress: unknown key")
+	assert.Contains(t, err.Error(), "server.level: invalid value trace, failed on oneof validation")
+}
+
+func TestSchemaValidation(t *testing.T) {
+	_, err := config.NewConfig(
+		config.WithValues(map[string]any{
+			"server.level": "trace",
+		}),
+		config.WithValidators(config.NewSchema[testSettings]("server").Validate),
+	)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "invalid config")
+	assert.Contains(t, err.Error(), "server.level")
+}
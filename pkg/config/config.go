@@ -1,40 +1,73 @@
 package config
 
 import (
-	"embed"
+	"bytes"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	*viper.Viper
-	env string
+	snapshot atomic.Pointer[snapshot]
+	options  *Options
+	// reload state
+	mu            sync.Mutex
+	reloadMu      sync.Mutex
+	watcher       *fsnotify.Watcher
+	subscriptions []subscription
+	reloadHooks   []func(error)
 }
 
-func NewConfig(opts ...Option) (*Config, error) {
-	v := viper.New()
-
-	// Configure defaults
-	v.SetDefault("app.name", "oryn")
-	v.SetDefault("app.version", "0.0.1")
-	v.SetDefault("app.debug", false)
-
-	// Configure environment variables support
-	v.SetEnvPrefix("ORYN")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
-	v.AutomaticEnv()
+// snapshot is the loaded state of the config, replaced as a whole on reload.
+type snapshot struct {
+	viper   *viper.Viper
+	env     string
+	envs    []string
+	origins map[string]string
+	secrets map[string]string
+	files   map[string]struct{}
+}
 
+// NewConfig loads the config from its sources, in the following order of precedence (last wins):
+//   - the embed FS root directory files
+//   - the embed FS environments then profiles directories files
+//   - for each external source, in their registration order, the root directory files then the environments then profiles directories files
+//   - the overlay file or directory
+//   - the ORYN_ prefixed environment variables
+//   - the values provided with WithValues
+//
+// The environments chain comes from ORYN_ENV or WithEnvironment, as a comma separated list like prod,prod-eu.
+// An environment or profile directory file can also set an extends key (like extends: prod) to load other environments before it.
+//
+// The config is reloaded on changes of the local directories and the overlay if ORYN_CONFIG_WATCH is true, or with WithWatch.
+//
+// The placeholders are resolved in the files of all sources, see resolvePlaceholders.
+// The secret://name values are then resolved with the secret providers, see SecretProvider.
+func NewConfig(opts ...Option) (*Config, error) {
 	// Default options
 	options := &Options{
 		env: strings.ToLower(os.Getenv("ORYN_ENV")),
 	}
 
+	if value := os.Getenv("ORYN_CONFIG_WATCH"); value != "" {
+		watch, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ORYN_CONFIG_WATCH value %s: %w", value, err)
+		}
+
+		options.watch = watch
+	}
+
 	// Apply options
 	for _, opt := range opts {
 		if err := opt(options); err != nil {
@@ -42,43 +75,180 @@ func NewConfig(opts ...Option) (*Config, error) {
 		}
 	}
 
-	// Load config files if embedFS is provided
+	snap, err := load(options)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		options: options,
+	}
+
+	cfg.snapshot.Store(snap)
+
+	// Validate the initial config
+	if err := validate(cfg, options.validators); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Env returns the most specific environment, the last of the environments chain.
+func (c *Config) Env() string {
+	return c.snapshot.Load().env
+}
+
+// Envs returns the resolved environments and profiles, in their loading order.
+func (c *Config) Envs() []string {
+	return slices.Clone(c.snapshot.Load().envs)
+}
+
+func (c *Config) TestEnv() bool {
+	return slices.Contains(c.snapshot.Load().envs, "test")
+}
+
+// Viper returns the current snapshot of the underlying viper instance.
+// The snapshot is replaced on each successful reload, so it should not be retained.
+func (c *Config) Viper() *viper.Viper {
+	return c.snapshot.Load().viper
+}
+
+func load(options *Options) (*snapshot, error) {
+	l := &loader{
+		viper:   viper.New(),
+		origins: map[string]string{},
+		files:   map[string]struct{}{},
+	}
+
+	// Configure defaults
+	l.setDefault("app.name", "oryn")
+	l.setDefault("app.version", "0.0.1")
+	l.setDefault("app.debug", false)
+
+	// Configure environment variables support
+	l.viper.SetEnvPrefix("ORYN")
+	l.viper.SetEnvKeyReplacer(envKeyReplacer)
+	l.viper.AutomaticEnv()
+
+	// Load config files from the embed FS first, then from the external sources
+	sources := []source{}
 	if options.embedFS != nil {
-		// Load all YAML files from the root config directory
-		if err := loadConfigFiles(v, *options.embedFS, "."); err != nil {
-			return nil, fmt.Errorf("failed to load embed root config files: %w", err)
+		sources = append(sources, source{name: "embed", fs: options.embedFS})
+	}
+
+	sources = append(sources, options.sources...)
+
+	// Resolve the environments chain, followed by the profiles
+	envs, env, err := resolveEnvs(sources, splitEnvs(options.env), options.profiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config environments: %w", err)
+	}
+
+	for _, src := range sources {
+		// Load all config files from the source root directory
+		if err := l.loadConfigFiles(src.name, src.fs, "."); err != nil {
+			return nil, fmt.Errorf("failed to load %s root config files: %w", src.name, err)
 		}
 
-		// Check if ORYN_ENV is set and load environment-specific config files
-		if options.env != "" {
-			if err := loadConfigFiles(v, *options.embedFS, filepath.Join(".", options.env)); err != nil {
-				return nil, fmt.Errorf("failed to load embed %s config files: %w", options.env, err)
+		// Load environment-specific and profile-specific config files
+		for _, env := range envs {
+			if err := l.loadConfigFiles(src.name, src.fs, filepath.Join(".", env)); err != nil {
+				return nil, fmt.Errorf("failed to load %s %s config files: %w", src.name, env, err)
 			}
 		}
 	}
 
-	// Merge programmatically provided values if any
-	if options.values != nil {
-		if err := v.MergeConfigMap(options.values); err != nil {
-			return nil, fmt.Errorf("failed to merge values: %w", err)
+	// Load the local overlay file or directory if provided
+	if options.overlay != "" {
+		if err := l.loadOverlay(options.overlay); err != nil {
+			return nil, fmt.Errorf("failed to load overlay config %s: %w", options.overlay, err)
+		}
+	}
+
+	// Track the keys overridden by environment variables
+	for _, key := range l.viper.AllKeys() {
+		envVar := "ORYN_" + strings.ToUpper(envKeyReplacer.Replace(key))
+		if os.Getenv(envVar) != "" {
+			l.origins[key] = "env:" + envVar
 		}
 	}
 
-	return &Config{
-		Viper: v,
-		env:   options.env,
+	// Set programmatically provided values if any, they take precedence over environment variables
+	for key, value := range flattenValues("", options.values) {
+		l.viper.Set(key, value)
+		l.origins[strings.ToLower(key)] = "values"
+	}
+
+	// Resolve secret references with the secret providers
+	secrets, err := resolveSecrets(l.viper, options.secretProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, provider := range secrets {
+		l.origins[key] = fmt.Sprintf("%s, secret:%s", l.origins[key], provider)
+	}
+
+	return &snapshot{
+		viper:   l.viper,
+		env:     env,
+		envs:    envs,
+		origins: l.origins,
+		secrets: secrets,
+		files:   l.files,
 	}, nil
 }
 
-func (c *Config) Env() string {
-	return c.env
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// flattenValues flattens nested maps into dotted keys, so each value overrides a single key.
+func flattenValues(prefix string, values map[string]any) map[string]any {
+	flattened := make(map[string]any, len(values))
+
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			maps.Copy(flattened, flattenValues(key, nested))
+
+			continue
+		}
+
+		flattened[key] = value
+	}
+
+	return flattened
 }
 
-func (c *Config) TestEnv() bool {
-	return c.env == "test"
+// loader loads the config, and tracks the origin of each key, and the keys read from files by placeholders.
+type loader struct {
+	viper   *viper.Viper
+	origins map[string]string
+	files   map[string]struct{}
 }
 
-func loadConfigFiles(v *viper.Viper, configFS embed.FS, configFSDir string) error {
+func (l *loader) setDefault(key string, value any) {
+	l.viper.SetDefault(key, value)
+	l.origins[key] = "default"
+}
+
+func (l *loader) loadOverlay(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return l.loadConfigFiles("overlay:"+path, os.DirFS(path), ".")
+	}
+
+	return l.loadConfigFile("overlay:"+filepath.Dir(path), os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+func (l *loader) loadConfigFiles(sourceName string, configFS fs.FS, configFSDir string) error {
 	entries, err := fs.ReadDir(configFS, configFSDir)
 	if err != nil {
 		// If the directory doesn't exist (e.g., prod folder), returns without error
@@ -89,58 +259,79 @@ func loadConfigFiles(v *viper.Viper, configFS embed.FS, configFSDir string) erro
 		return fmt.Errorf("failed to read config directory %s: %w", configFSDir, err)
 	}
 
-	// Compile a regexp to match environment variable placeholders
-	envVarPattern, err := regexp.Compile(`\$\{([^}]+)\}`)
-	if err != nil {
-		return fmt.Errorf("failed to compile config env var pattern: %w", err)
-	}
-
 	for _, entry := range entries {
 		// Skip directories
 		if entry.IsDir() {
 			continue
 		}
 
-		fileName := entry.Name()
-
-		// Detect the file format based on extension
-		var configType string
-		if strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml") {
-			configType = "yaml"
-		} else if strings.HasSuffix(fileName, ".json") {
-			configType = "json"
-		} else {
-			// Skip unsupported file types
-			continue
+		if err := l.loadConfigFile(sourceName, configFS, filepath.Join(configFSDir, entry.Name())); err != nil {
+			return err
 		}
+	}
 
-		// Read the file content
-		filePath := filepath.Join(configFSDir, fileName)
-		data, err := fs.ReadFile(configFS, filePath)
-		if err != nil {
-			return fmt.Errorf("failed to read config file %s: %w", filePath, err)
-		}
+	return nil
+}
 
-		// Resolve environment variable placeholders matching for example ${ENV_VAR}
-		resolvedData := envVarPattern.ReplaceAllStringFunc(string(data), func(match string) string {
-			// Extract the env var name from the placeholder
-			envVar := envVarPattern.FindStringSubmatch(match)
-			if len(envVar) < 2 {
-				return ""
-			}
+func (l *loader) loadConfigFile(sourceName string, configFS fs.FS, filePath string) error {
+	// Detect the file format based on extension
+	configType := configFileType(filePath)
+	if configType == "" {
+		// Skip unsupported file types
+		return nil
+	}
+
+	// Read the file content
+	data, err := fs.ReadFile(configFS, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", filePath, err)
+	}
+
+	// Parse the file on its own to track the origin of its keys
+	fileViper := viper.New()
+	fileViper.SetConfigType(configType)
 
-			// Returns the environment variable value, if not set, returns empty string
-			return os.Getenv(envVar[1])
-		})
+	if err := fileViper.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to parse config from %s: %w", filePath, err)
+	}
+
+	// Resolve the placeholders of the parsed values, for example ${ENV_VAR}, ${ENV_VAR:-default}, ${ENV_VAR:?error} or ${file:/path}
+	settings := fileViper.AllSettings()
 
-		// Set the config type
-		v.SetConfigType(configType)
+	files, err := resolvePlaceholders(settings, filePath)
+	if err != nil {
+		return err
+	}
+
+	// Merge the config, the environments inheritance is not part of it
+	delete(settings, ExtendsKey)
+
+	if err := l.viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to merge config from %s: %w", filePath, err)
+	}
 
-		// Merge the config
-		if err := v.MergeConfig(strings.NewReader(resolvedData)); err != nil {
-			return fmt.Errorf("failed to merge config from %s: %w", filePath, err)
+	for _, key := range fileViper.AllKeys() {
+		if key != ExtendsKey {
+			l.origins[key] = sourceName + ":" + filepath.ToSlash(filePath)
+		}
+
+		if _, ok := files[key]; ok {
+			l.files[key] = struct{}{}
+		} else {
+			delete(l.files, key)
 		}
 	}
 
 	return nil
 }
+
+func configFileType(fileName string) string {
+	switch {
+	case strings.HasSuffix(fileName, ".yaml"), strings.HasSuffix(fileName, ".yml"):
+		return "yaml"
+	case strings.HasSuffix(fileName, ".json"):
+		return "json"
+	default:
+		return ""
+	}
+}
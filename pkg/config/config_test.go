@@ -0,0 +1,229 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg, err := config.NewConfig(
+		config.WithEnvironment("test"),
+		config.WithValues(map[string]any{
+			"app.name": "test app",
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test", cfg.Env())
+	assert.True(t, cfg.TestEnv())
+	assert.Equal(t, "test app", cfg.GetString("app.name"))
+	assert.Equal(t, "0.0.1", cfg.GetString("app.version"))
+}
+
+func TestNewConfigPrecedence(t *testing.T) {
+	t.Setenv("APP_NAME", "prod app")
+	t.Setenv("ORYN_DATABASE_USER", "env-user")
+
+	external := t.TempDir()
+	writeFile(t, filepath.Join(external, "database.yaml"), "database:\n  host: external\n  port: 3306\n")
+	require.NoError(t, os.Mkdir(filepath.Join(external, "prod"), 0o700))
+	writeFile(t, filepath.Join(external, "prod", "database.yaml"), "database:\n  host: external-prod\n")
+
+	cfg, err := config.NewConfig(
+		config.WithEnvironment("prod"),
+		config.WithFS(os.DirFS("testdata/yaml")),
+		config.WithDirectory(external),
+		config.WithValues(map[string]any{
+			"database": map[string]any{
+				"password": "values-password",
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	// first source env file, with resolved placeholder
+	assert.Equal(t, "prod app", cfg.GetString("app.name"))
+	// root file only
+	assert.Equal(t, "myapp", cfg.GetString("database.name"))
+	// external root overrides first source root
+	assert.Equal(t, 3306, cfg.GetInt("database.port"))
+	// external env overrides external root
+	assert.Equal(t, "external-prod", cfg.GetString("database.host"))
+	// env vars override files
+	assert.Equal(t, "env-user", cfg.GetString("database.user"))
+	// values override env vars
+	t.Setenv("ORYN_DATABASE_PASSWORD", "env-password")
+	assert.Equal(t, "values-password", cfg.GetString("database.password"))
+
+	// provenance, with sensitive values redacted
+	assert.Equal(t, []config.Setting{
+		{Key: "database.host", Value: "external-prod", Source: external + ":prod/database.yaml"},
+		{Key: "database.name", Value: "myapp", Source: "fs#0:database.yaml"},
+		{Key: "database.password", Value: config.Redacted, Source: "values"},
+		{Key: "database.port", Value: 3306, Source: external + ":database.yaml"},
+		{Key: "database.user", Value: "env-user", Source: "env:ORYN_DATABASE_USER"},
+	}, cfg.Settings("database"))
+}
+
+func TestNewConfigEnvironments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.yaml"), "app:\n  name: root\n  region: none\n")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "prod"), 0o700))
+	writeFile(t, filepath.Join(dir, "prod", "app.yaml"), "app:\n  name: prod\n  region: us\n")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "prod-eu"), 0o700))
+	writeFile(t, filepath.Join(dir, "prod-eu", "app.yaml"), "extends: prod\napp:\n  region: eu\n")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "canary"), 0o700))
+	writeFile(t, filepath.Join(dir, "canary", "app.yaml"), "app:\n  canary: true\n")
+
+	t.Run("extends", func(t *testing.T) {
+		cfg, err := config.NewConfig(config.WithEnvironment("prod-eu"), config.WithDirectory(dir))
+		require.NoError(t, err)
+
+		assert.Equal(t, "prod-eu", cfg.Env())
+		assert.Equal(t, []string{"prod", "prod-eu"}, cfg.Envs())
+		assert.Equal(t, "prod", cfg.GetString("app.name"))
+		assert.Equal(t, "eu", cfg.GetString("app.region"))
+		assert.False(t, cfg.IsSet(config.ExtendsKey))
+	})
+
+	t.Run("chain and profiles", func(t *testing.T) {
+		cfg, err := config.NewConfig(
+			config.WithEnvironment("prod-eu,prod"),
+			config.WithProfiles("canary"),
+			config.WithDirectory(dir),
+		)
+		require.NoError(t, err)
+
+		// the most specific environment is the last loaded one, whatever its position in the chain
+		assert.Equal(t, "prod-eu", cfg.Env())
+		assert.Equal(t, []string{"prod", "prod-eu", "canary"}, cfg.Envs())
+		assert.Equal(t, "eu", cfg.GetString("app.region"))
+		assert.True(t, cfg.GetBool("app.canary"))
+	})
+
+	t.Run("cyclic", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "prod", "extends.yaml"), "extends: prod-eu\n")
+
+		_, err := config.NewConfig(config.WithEnvironment("prod-eu"), config.WithDirectory(dir))
+		require.ErrorContains(t, err, "cyclic environments inheritance: prod-eu -> prod -> prod-eu")
+	})
+}
+
+func TestReload(t *testing.T) {
+	overlay := filepath.Join(t.TempDir(), "logs.yaml")
+	writeFile(t, overlay, "logs:\n  level: debug\n")
+
+	cfg, err := config.NewConfig(
+		config.WithOverlay(overlay),
+		config.WithValidators(func(cfg *config.Config) error {
+			if cfg.GetString("logs.level") == "invalid" {
+				return errors.New("invalid logs level")
+			}
+
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	var changes []any
+	cfg.OnChange("logs.level", func(_, newValue any) {
+		changes = append(changes, newValue)
+	})
+
+	// valid change
+	writeFile(t, overlay, "logs:\n  level: info\n")
+	require.NoError(t, cfg.Reload())
+	assert.Equal(t, "info", cfg.GetString("logs.level"))
+
+	// malformed file keeps the previous config
+	writeFile(t, overlay, "logs: [")
+	require.Error(t, cfg.Reload())
+	assert.Equal(t, "info", cfg.GetString("logs.level"))
+
+	// invalid value keeps the previous config
+	writeFile(t, overlay, "logs:\n  level: invalid\n")
+	require.Error(t, cfg.Reload())
+	assert.Equal(t, "info", cfg.GetString("logs.level"))
+
+	assert.Equal(t, []any{"info"}, changes)
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "logs.yaml"), "logs:\n  level: debug\n")
+
+	cfg, err := config.NewConfig(config.WithOverlay(dir), config.WithWatch())
+	require.NoError(t, err)
+
+	changed := make(chan any, 1)
+	cfg.OnChange("logs.level", func(_, newValue any) {
+		changed <- newValue
+	})
+
+	require.NoError(t, cfg.Watch())
+	defer cfg.Close()
+
+	writeFile(t, filepath.Join(dir, "logs.yaml"), "logs:\n  level: warn\n")
+
+	select {
+	case value := <-changed:
+		assert.Equal(t, "warn", value)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
+
+func TestWatchFromEnv(t *testing.T) {
+	t.Setenv("ORYN_CONFIG_WATCH", "true")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "logs.yaml"), "logs:\n  level: debug\n")
+
+	var cfg *config.Config
+
+	app := fxtest.New(
+		t,
+		config.Module,
+		config.AsConfigOptions(config.WithDirectory(dir)),
+		fx.Populate(&cfg),
+	)
+
+	changed := make(chan any, 1)
+	cfg.OnChange("logs.level", func(_, newValue any) {
+		changed <- newValue
+	})
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	writeFile(t, filepath.Join(dir, "logs.yaml"), "logs:\n  level: warn\n")
+
+	select {
+	case value := <-changed:
+		assert.Equal(t, "warn", value)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
+
+func TestWatchFromEnvInvalid(t *testing.T) {
+	t.Setenv("ORYN_CONFIG_WATCH", "sometimes")
+
+	_, err := config.NewConfig()
+	require.ErrorContains(t, err, "invalid ORYN_CONFIG_WATCH value sometimes")
+}
+
+func writeFile(tb testing.TB, path string, content string) {
+	tb.Helper()
+
+	require.NoError(tb, os.WriteFile(path, []byte(content), 0o600))
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces the secret and sensitive values when the config is dumped or logged.
+const Redacted = "******"
+
+// sensitiveKeyPattern matches the keys considered sensitive from their name, the headers values,
+// and the db replicas DSNs lists.
+var sensitiveKeyPattern = regexp.MustCompile(
+	`(?i)((dsn|password|passwd|secret|token|api_?key|private_?key|credentials?|authorization|cookie)$|(^|\.)headers\.[^.]+$|` +
+		`^db\.(connections\.[^.]+\.)?replicas(\.\d+)?$)`,
+)
+
+// Setting is a config key, with its effective value and the source it comes from.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// Settings returns the settings of the keys under the prefix (all keys if empty) sorted by key,
+// with the secret and sensitive values redacted.
+func (c *Config) Settings(prefix string) []Setting {
+	snap := c.snapshot.Load()
+
+	prefix = strings.ToLower(prefix)
+
+	keys := snap.viper.AllKeys()
+	slices.Sort(keys)
+
+	settings := make([]Setting, 0, len(keys))
+
+	for _, key := range keys {
+		if prefix != "" && key != prefix && !strings.HasPrefix(key, prefix+".") {
+			continue
+		}
+
+		value := snap.viper.Get(key)
+		if snap.redacted(key) {
+			value = Redacted
+		}
+
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  value,
+			Source: snap.origins[key],
+		})
+	}
+
+	return settings
+}
+
+// Redacted returns all the settings, with the secret and sensitive values redacted.
+func (c *Config) Redacted() map[string]any {
+	snap := c.snapshot.Load()
+
+	settings := snap.viper.AllSettings()
+
+	for _, key := range snap.viper.AllKeys() {
+		if !snap.redacted(key) {
+			continue
+		}
+
+		path := strings.Split(key, ".")
+
+		current := settings
+		for _, part := range path[:len(path)-1] {
+			next, ok := current[part].(map[string]any)
+			if !ok {
+				break
+			}
+
+			current = next
+		}
+
+		if _, ok := current[path[len(path)-1]]; ok {
+			current[path[len(path)-1]] = Redacted
+		}
+	}
+
+	return settings
+}
+
+// LogValue implements slog.LogValuer, so logging the config never leaks its secrets.
+func (c *Config) LogValue() slog.Value {
+	return slog.AnyValue(c.Redacted())
+}
+
+// redacted returns true for the keys resolved from a secret provider or read from a file, and the sensitive keys.
+func (s *snapshot) redacted(key string) bool {
+	if _, ok := s.secrets[key]; ok {
+		return true
+	}
+
+	if _, ok := s.files[key]; ok {
+		return true
+	}
+
+	return sensitiveKeyPattern.MatchString(key)
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ExtendsKey is the config key an environment directory file can set to inherit from other environments.
+const ExtendsKey = "extends"
+
+// splitEnvs splits a comma separated environments chain, for example prod,prod-eu.
+func splitEnvs(env string) []string {
+	var envs []string
+
+	for _, e := range strings.Split(env, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			envs = append(envs, e)
+		}
+	}
+
+	return envs
+}
+
+// resolveEnvs returns the environments and profiles directories to load in order,
+// with the environments they extend (recursively) loaded before them,
+// and the most specific environment, the last loaded one of the environments chain.
+func resolveEnvs(sources []source, envs []string, profiles []string) ([]string, string, error) {
+	var resolved []string
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if slices.Contains(path, name) {
+			return fmt.Errorf("cyclic environments inheritance: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		if slices.Contains(resolved, name) {
+			return nil
+		}
+
+		parents, err := envExtends(sources, name)
+		if err != nil {
+			return err
+		}
+
+		for _, parent := range parents {
+			if err = visit(parent, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		resolved = append(resolved, name)
+
+		return nil
+	}
+
+	for _, name := range envs {
+		if err := visit(name, nil); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var env string
+	if len(resolved) > 0 {
+		env = resolved[len(resolved)-1]
+	}
+
+	for _, name := range profiles {
+		if err := visit(name, nil); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return resolved, env, nil
+}
+
+// envExtends returns the environments extended by the files of an environment directory, in all sources.
+func envExtends(sources []source, env string) ([]string, error) {
+	var extends []string
+
+	for _, src := range sources {
+		entries, err := fs.ReadDir(src.fs, env)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to read %s %s config directory: %w", src.name, env, err)
+		}
+
+		for _, entry := range entries {
+			configType := configFileType(entry.Name())
+			if entry.IsDir() || configType == "" {
+				continue
+			}
+
+			filePath := filepath.Join(env, entry.Name())
+
+			data, err := fs.ReadFile(src.fs, filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read config file %s: %w", filePath, err)
+			}
+
+			v := viper.New()
+			v.SetConfigType(configType)
+
+			if err = v.ReadConfig(bytes.NewReader(data)); err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %w", filePath, err)
+			}
+
+			for _, parent := range v.GetStringSlice(ExtendsKey) {
+				if !slices.Contains(extends, parent) {
+					extends = append(extends, parent)
+				}
+			}
+		}
+	}
+
+	return extends, nil
+}
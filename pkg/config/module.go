@@ -1,6 +1,10 @@
 package config
 
-import "go.uber.org/fx"
+import (
+	"context"
+
+	"go.uber.org/fx"
+)
 
 const ModuleName = "config"
 
@@ -13,9 +17,35 @@ var Module = fx.Module(
 
 type ProvideConfigParams struct {
 	fx.In
-	Options []Option `group:"config-options"`
+	Lifecycle fx.Lifecycle
+	Options   []Option         `group:"config-options"`
+	Schemas   []Schema         `group:"config-schemas"`
+	Providers []SecretProvider `group:"config-secret-providers"`
 }
 
 func ProvideConfig(params ProvideConfigParams) (*Config, error) {
-	return NewConfig(params.Options...)
+	validators := make([]Validator, 0, len(params.Schemas))
+	for _, schema := range params.Schemas {
+		validators = append(validators, schema.Validate)
+	}
+
+	cfg, err := NewConfig(append(
+		params.Options,
+		WithValidators(validators...),
+		WithSecretProviders(params.Providers...),
+	)...)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			return cfg.Watch()
+		},
+		OnStop: func(context.Context) error {
+			return cfg.Close()
+		},
+	})
+
+	return cfg, nil
 }
@@ -1,15 +1,37 @@
 package config
 
-import "embed"
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+)
 
 type Options struct {
-	env     string
-	embedFS *embed.FS
-	values  map[string]any
+	env        string
+	profiles   []string
+	embedFS    fs.FS
+	sources    []source
+	overlay    string
+	watch      bool
+	values     map[string]any
+	validators []Validator
+	// secrets
+	secretProviders []SecretProvider
 }
 
 type Option func(*Options) error
 
+// source is an external config source, path is set for the local directories that can be watched.
+type source struct {
+	name string
+	fs   fs.FS
+	path string
+}
+
+// Validator validates a loaded config, it is invoked at startup and before each reload is applied.
+type Validator func(cfg *Config) error
+
 func WithEnvironment(env string) Option {
 	return func(o *Options) error {
 		o.env = env
@@ -18,9 +40,79 @@ func WithEnvironment(env string) Option {
 	}
 }
 
+// WithProfiles loads the profiles directories files after the environments ones, in order.
+func WithProfiles(profiles ...string) Option {
+	return func(o *Options) error {
+		o.profiles = append(o.profiles, profiles...)
+
+		return nil
+	}
+}
+
 func WithEmbedFS(fs embed.FS) Option {
 	return func(o *Options) error {
-		o.embedFS = &fs
+		o.embedFS = fs
+
+		return nil
+	}
+}
+
+// WithDirectory loads the config files from a local directory (and its environment subdirectory) after the embedded ones.
+// A missing directory is ignored, so optional mounts (like Kubernetes ConfigMaps) don't need to exist.
+func WithDirectory(path string) Option {
+	return func(o *Options) error {
+		o.sources = append(o.sources, source{
+			name: path,
+			fs:   os.DirFS(path),
+			path: path,
+		})
+
+		return nil
+	}
+}
+
+// WithFS loads the config files from a filesystem (and its environment subdirectory) after the embedded ones.
+func WithFS(fsys fs.FS) Option {
+	return func(o *Options) error {
+		o.sources = append(o.sources, source{
+			name: fmt.Sprintf("fs#%d", len(o.sources)),
+			fs:   fsys,
+		})
+
+		return nil
+	}
+}
+
+// WithOverlay merges a local config file or directory on top of the embedded config files.
+func WithOverlay(path string) Option {
+	return func(o *Options) error {
+		o.overlay = path
+
+		return nil
+	}
+}
+
+// WithWatch enables the reload of the config when the local directories or the overlay change.
+func WithWatch() Option {
+	return func(o *Options) error {
+		o.watch = true
+
+		return nil
+	}
+}
+
+func WithValidators(validators ...Validator) Option {
+	return func(o *Options) error {
+		o.validators = append(o.validators, validators...)
+
+		return nil
+	}
+}
+
+// WithSecretProviders registers secret providers used to resolve the secret://name values, in order.
+func WithSecretProviders(providers ...SecretProvider) Option {
+	return func(o *Options) error {
+		o.secretProviders = append(o.secretProviders, providers...)
 
 		return nil
 	}
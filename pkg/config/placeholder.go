@@ -0,0 +1,145 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// placeholderPattern matches the placeholders, for example ${ENV_VAR}, ${ENV_VAR:-default}, ${ENV_VAR:?error} or ${file:/path}.
+var placeholderPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// resolvePlaceholders resolves in place the placeholders of the string values of a parsed config file:
+//   - ${ENV_VAR} is replaced by the environment variable value, or by an empty string if not set
+//   - ${ENV_VAR:-default} is replaced by the environment variable value, or by default if not set or empty
+//   - ${ENV_VAR:?message} is replaced by the environment variable value, or fails with message if not set or empty
+//   - ${file:/path} is replaced by the content of the file (without trailing newlines), or fails if it cannot be read
+//
+// Since the values are resolved after parsing, they are used as is, and the placeholders of the comments are ignored.
+// It returns the keys holding a value read from a file, redacted as secrets whatever their name: the lists keys
+// for the values of their items, since the lists are leaf values.
+func resolvePlaceholders(settings map[string]any, filePath string) (map[string]struct{}, error) {
+	files := map[string]struct{}{}
+
+	errs := resolveMap("", "", settings, files)
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("config file %s: %w", filePath, err)
+	}
+
+	return files, errors.Join(errs...)
+}
+
+// resolveMap resolves the values of a map under the prefix, within the list if not empty.
+func resolveMap(prefix string, list string, values map[string]any, files map[string]struct{}) []error {
+	var errs []error
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		var keyErrs []error
+		values[key], keyErrs = resolveValue(joinKey(prefix, key), list, values[key], files)
+		errs = append(errs, keyErrs...)
+	}
+
+	return errs
+}
+
+func resolveValue(key string, list string, value any, files map[string]struct{}) (any, []error) {
+	switch v := value.(type) {
+	case string:
+		resolved, fromFile, err := resolveString(v)
+		if err != nil {
+			return v, []error{fmt.Errorf("key %s: %w", key, err)}
+		}
+
+		if fromFile {
+			files[cmp.Or(list, key)] = struct{}{}
+		}
+
+		return resolved, nil
+	case map[string]any:
+		return v, resolveMap(key, list, v, files)
+	case []any:
+		var errs []error
+		for i, item := range v {
+			var itemErrs []error
+			v[i], itemErrs = resolveValue(joinKey(key, strconv.Itoa(i)), cmp.Or(list, key), item, files)
+			errs = append(errs, itemErrs...)
+		}
+
+		return v, errs
+	default:
+		return value, nil
+	}
+}
+
+// resolveString resolves the placeholders of the value, and returns true if one of them was read from a file.
+func resolveString(value string) (string, bool, error) {
+	var (
+		errs     []error
+		fromFile bool
+	)
+
+	resolved := placeholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+		expression := placeholderPattern.FindStringSubmatch(match)[1]
+		fromFile = fromFile || strings.HasPrefix(expression, "file:")
+
+		value, err := resolvePlaceholder(expression)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		return value
+	})
+
+	return resolved, fromFile, errors.Join(errs...)
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func resolvePlaceholder(expression string) (string, error) {
+	// File content
+	if path, ok := strings.CutPrefix(expression, "file:"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("cannot read file: %w", err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	// Environment variable with default value
+	if name, defaultValue, ok := strings.Cut(expression, ":-"); ok {
+		if value := os.Getenv(name); value != "" {
+			return value, nil
+		}
+
+		return defaultValue, nil
+	}
+
+	// Required environment variable
+	if name, message, ok := strings.Cut(expression, ":?"); ok {
+		if value := os.Getenv(name); value != "" {
+			return value, nil
+		}
+
+		if message == "" {
+			message = fmt.Sprintf("environment variable %s is required", name)
+		}
+
+		return "", errors.New(message)
+	}
+
+	// Returns the environment variable value, if not set, returns empty string
+	return os.Getenv(expression), nil
+}
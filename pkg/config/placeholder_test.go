@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceholders(t *testing.T) {
+	t.Setenv("DB_HOST", "db-host")
+	t.Setenv("DB_PORT", "")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "password"), "secret\n")
+	writeFile(t, filepath.Join(dir, "db.yaml"), `
+database:
+  host: ${DB_HOST:?database host is required}
+  port: ${DB_PORT:-5432}
+  user: ${DB_USER}
+  password: ${file:`+filepath.Join(dir, "password")+`}
+`)
+
+	cfg, err := config.NewConfig(config.WithDirectory(dir))
+	require.NoError(t, err)
+
+	assert.Equal(t, "db-host", cfg.GetString("database.host"))
+	assert.Equal(t, 5432, cfg.GetInt("database.port"))
+	assert.Equal(t, "", cfg.GetString("database.user"))
+	assert.Equal(t, "secret", cfg.GetString("database.password"))
+}
+
+func TestPlaceholdersFilesRedaction(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ca.pem"), "ca")
+	writeFile(t, filepath.Join(dir, "db.yaml"), `
+database:
+  ca: ${file:`+filepath.Join(dir, "ca.pem")+`}
+  certs:
+    - ${file:`+filepath.Join(dir, "ca.pem")+`}
+    - plain
+  overridden: ${file:`+filepath.Join(dir, "ca.pem")+`}
+  host: localhost
+`)
+	writeFile(t, filepath.Join(dir, "override.yaml"), `
+database:
+  overridden: plain
+`)
+
+	cfg, err := config.NewConfig(config.WithDirectory(dir))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ca", cfg.GetString("database.ca"))
+	assert.Equal(t, []config.Setting{
+		{Key: "database.ca", Value: config.Redacted, Source: dir + ":db.yaml"},
+		{Key: "database.certs", Value: config.Redacted, Source: dir + ":db.yaml"},
+		{Key: "database.host", Value: "localhost", Source: dir + ":db.yaml"},
+		{Key: "database.overridden", Value: "plain", Source: dir + ":override.yaml"},
+	}, cfg.Settings("database"))
+
+	redacted := cfg.Redacted()["database"].(map[string]any)
+	assert.Equal(t, config.Redacted, redacted["ca"])
+	assert.Equal(t, config.Redacted, redacted["certs"])
+}
+
+func TestPlaceholdersErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "db.yaml"), `
+database:
+  host: ${DB_HOST:?database host is required}
+  password: ${file:/nonexistent/password}
+`)
+
+	_, err := config.NewConfig(config.WithDirectory(dir))
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "config file db.yaml: key database.host: database host is required")
+	assert.Contains(t, err.Error(), "config file db.yaml: key database.password: cannot read file")
+}
+
+func TestPlaceholdersSpecialValues(t *testing.T) {
+	t.Setenv("DB_PASSWORD", `p@ss: "word" # not a comment`)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "key.pem"), "-----BEGIN KEY-----\nline: 1\nextra: injected\n-----END KEY-----\n")
+	writeFile(t, filepath.Join(dir, "token"), `'quoted' "token" \ with: colon # and hash`)
+	writeFile(t, filepath.Join(dir, "db.yaml"), `
+database:
+  # required ${DB_HOST:?ignored in comments}
+  password: ${DB_PASSWORD}
+  key: ${file:`+filepath.Join(dir, "key.pem")+`}
+  token: "${file:`+filepath.Join(dir, "token")+`}"
+  hosts:
+    - ${DB_HOST:-primary}
+    - replica
+`)
+
+	cfg, err := config.NewConfig(config.WithDirectory(dir))
+	require.NoError(t, err)
+
+	assert.Equal(t, `p@ss: "word" # not a comment`, cfg.GetString("database.password"))
+	assert.Equal(t, "-----BEGIN KEY-----\nline: 1\nextra: injected\n-----END KEY-----", cfg.GetString("database.key"))
+	assert.Equal(t, `'quoted' "token" \ with: colon # and hash`, cfg.GetString("database.token"))
+	assert.Equal(t, []string{"primary", "replica"}, cfg.GetStringSlice("database.hosts"))
+	assert.False(t, cfg.IsSet("extra"))
+	assert.False(t, cfg.IsSet("database.extra"))
+}
@@ -18,3 +18,28 @@ func AsConfigOptions(options ...Option) fx.Option {
 
 	return fx.Options(fxOptions...)
 }
+
+func AsSchema[T any](section string) fx.Option {
+	return AsCustomSchema(NewSchema[T](section))
+}
+
+// AsCustomSchema registers a Schema for the sections that cannot be bound into a single struct.
+func AsCustomSchema(schema Schema) fx.Option {
+	return fx.Supply(
+		fx.Annotate(
+			schema,
+			fx.As(new(Schema)),
+			fx.ResultTags(`group:"config-schemas"`),
+		),
+	)
+}
+
+func AsSecretProvider(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(SecretProvider)),
+			fx.ResultTags(`group:"config-secret-providers"`),
+		),
+	)
+}
@@ -0,0 +1,28 @@
+package config
+
+// Schema validates a config section, see Bind.
+type Schema interface {
+	Section() string
+	Validate(cfg *Config) error
+}
+
+type schema[T any] struct {
+	section string
+}
+
+// NewSchema returns a Schema binding the config section into a T struct.
+func NewSchema[T any](section string) Schema {
+	return &schema[T]{
+		section: section,
+	}
+}
+
+func (s *schema[T]) Section() string {
+	return s.section
+}
+
+func (s *schema[T]) Validate(cfg *Config) error {
+	_, err := Bind[T](cfg, s.section)
+
+	return err
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// SecretScheme prefixes the config values to resolve with the secret providers, for example secret://db_dsn.
+const SecretScheme = "secret://"
+
+// ErrSecretNotFound is returned by a SecretProvider that does not hold the requested secret,
+// so the next provider can be tried.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider resolves secrets by name.
+type SecretProvider interface {
+	Name() string
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// resolveSecrets replaces the secret://name values by the secret of the first provider holding it, including within
+// the lists and maps values, and returns the keys holding resolved secrets, with the name of their providers.
+func resolveSecrets(v *viper.Viper, providers []SecretProvider) (map[string]string, error) {
+	secrets := map[string]string{}
+
+	var errs []error
+
+	keys := v.AllKeys()
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		resolver := &secretResolver{providers: providers}
+
+		value := resolver.resolve(key, v.Get(key))
+		if len(resolver.errs) > 0 {
+			errs = append(errs, resolver.errs...)
+
+			continue
+		}
+
+		if len(resolver.names) == 0 {
+			continue
+		}
+
+		v.Set(key, value)
+		secrets[key] = strings.Join(resolver.names, ",")
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", errors.Join(errs...))
+	}
+
+	return secrets, nil
+}
+
+// secretResolver resolves the secret references of a value, recursively in the lists and maps, without modifying them.
+type secretResolver struct {
+	providers []SecretProvider
+	names     []string
+	errs      []error
+}
+
+func (r *secretResolver) resolve(key string, value any) any {
+	switch v := value.(type) {
+	case string:
+		name, ok := strings.CutPrefix(v, SecretScheme)
+		if !ok {
+			return v
+		}
+
+		secret, provider, err := lookupSecret(r.providers, name)
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("key %s: %w", key, err))
+
+			return v
+		}
+
+		if !slices.Contains(r.names, provider) {
+			r.names = append(r.names, provider)
+		}
+
+		return secret
+	case []string:
+		resolved := make([]string, len(v))
+		for i, item := range v {
+			resolved[i] = r.resolve(joinKey(key, strconv.Itoa(i)), item).(string)
+		}
+
+		return resolved
+	case []any:
+		resolved := make([]any, len(v))
+		for i, item := range v {
+			resolved[i] = r.resolve(joinKey(key, strconv.Itoa(i)), item)
+		}
+
+		return resolved
+	case map[string]any:
+		resolved := make(map[string]any, len(v))
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			resolved[name] = r.resolve(joinKey(key, name), v[name])
+		}
+
+		return resolved
+	default:
+		return value
+	}
+}
+
+func lookupSecret(providers []SecretProvider, name string) (string, string, error) {
+	for _, provider := range providers {
+		secret, err := provider.Secret(context.Background(), name)
+		if err == nil {
+			return secret, provider.Name(), nil
+		}
+
+		if !errors.Is(err, ErrSecretNotFound) {
+			return "", "", fmt.Errorf("secret %s: provider %s: %w", name, provider.Name(), err)
+		}
+	}
+
+	return "", "", fmt.Errorf("secret %s: %w", name, ErrSecretNotFound)
+}
+
+// IsSecret returns true if the key value was resolved from a secret provider.
+func (c *Config) IsSecret(key string) bool {
+	_, ok := c.snapshot.Load().secrets[strings.ToLower(key)]
+
+	return ok
+}
+
+// FileSecretProvider reads secrets from files named after them in a directory, like Docker or Kubernetes secrets mounts.
+type FileSecretProvider struct {
+	dir string
+}
+
+func NewFileSecretProvider(dir string) *FileSecretProvider {
+	return &FileSecretProvider{
+		dir: dir,
+	}
+}
+
+func (p *FileSecretProvider) Name() string {
+	return "file"
+}
+
+func (p *FileSecretProvider) Secret(_ context.Context, name string) (string, error) {
+	content, err := readSecretFile(p.dir, name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// EncryptedFileSecretProvider reads secrets from <name>.enc files in a directory, encrypted with AES-GCM.
+// The files contain the base64 encoding of the nonce followed by the ciphertext, see EncryptSecret.
+// The base64 encoded AES key (16, 24 or 32 bytes) is read from an environment variable.
+type EncryptedFileSecretProvider struct {
+	dir       string
+	keyEnvVar string
+}
+
+func NewEncryptedFileSecretProvider(dir string, keyEnvVar string) *EncryptedFileSecretProvider {
+	return &EncryptedFileSecretProvider{
+		dir:       dir,
+		keyEnvVar: keyEnvVar,
+	}
+}
+
+func (p *EncryptedFileSecretProvider) Name() string {
+	return "encrypted-file"
+}
+
+func (p *EncryptedFileSecretProvider) Secret(_ context.Context, name string) (string, error) {
+	content, err := readSecretFile(p.dir, name+".enc")
+	if err != nil {
+		return "", err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(p.keyEnvVar))
+	if err != nil || len(key) == 0 {
+		return "", fmt.Errorf("invalid or missing key in environment variable %s", p.keyEnvVar)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return "", fmt.Errorf("cannot decode encrypted secret: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	// the secret name is authenticated, so an encrypted file cannot be renamed to another secret
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt secret: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// EncryptSecret encrypts a secret for the EncryptedFileSecretProvider, and returns the content of its file.
+func EncryptSecret(key []byte, name string, secret string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), []byte(name))), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func readSecretFile(dir string, fileName string) ([]byte, error) {
+	if !filepath.IsLocal(fileName) {
+		return nil, fmt.Errorf("invalid secret name %s", fileName)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, fileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrSecretNotFound
+		}
+
+		return nil, err
+	}
+
+	return content, nil
+}
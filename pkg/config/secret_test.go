@@ -0,0 +1,149 @@
+package config_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecrets(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	t.Setenv("TEST_SECRETS_KEY", base64.StdEncoding.EncodeToString(key))
+
+	fileDir := t.TempDir()
+	writeFile(t, filepath.Join(fileDir, "db_password"), "file-password\n")
+
+	encryptedDir := t.TempDir()
+	encrypted, err := config.EncryptSecret(key, "api_token", "encrypted-token")
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(encryptedDir, "api_token.enc"), encrypted)
+
+	cfg, err := config.NewConfig(
+		config.WithValues(map[string]any{
+			"db.password": "secret://db_password",
+			"api.token":   "secret://api_token",
+			"api.url":     "https://example.com",
+			"api.headers": map[string]any{
+				"Authorization": "Bearer header-token",
+			},
+			"api.proxy_authorization": "Basic proxy-credentials",
+			"api.session_cookie":      "session=cookie-value",
+		}),
+		config.WithSecretProviders(
+			config.NewFileSecretProvider(fileDir),
+			config.NewEncryptedFileSecretProvider(encryptedDir, "TEST_SECRETS_KEY"),
+		),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-password", cfg.GetString("db.password"))
+	assert.Equal(t, "encrypted-token", cfg.GetString("api.token"))
+	assert.True(t, cfg.IsSecret("api.token"))
+	assert.False(t, cfg.IsSecret("api.url"))
+
+	// redaction
+	redacted := cfg.Redacted()
+	assert.Equal(t, config.Redacted, redacted["db"].(map[string]any)["password"])
+	assert.Equal(t, config.Redacted, redacted["api"].(map[string]any)["token"])
+	assert.Equal(t, "https://example.com", redacted["api"].(map[string]any)["url"])
+	assert.Equal(t, config.Redacted, redacted["api"].(map[string]any)["headers"].(map[string]any)["authorization"])
+	assert.Equal(t, config.Redacted, redacted["api"].(map[string]any)["proxy_authorization"])
+	assert.Equal(t, config.Redacted, redacted["api"].(map[string]any)["session_cookie"])
+
+	for _, setting := range cfg.Settings("api") {
+		if setting.Key != "api.url" {
+			assert.Equal(t, config.Redacted, setting.Value, setting.Key)
+		}
+	}
+
+	var buf strings.Builder
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("config", "config", cfg)
+	assert.NotContains(t, buf.String(), "file-password")
+	assert.NotContains(t, buf.String(), "encrypted-token")
+	assert.NotContains(t, buf.String(), "header-token")
+}
+
+func TestSecretsNotFound(t *testing.T) {
+	_, err := config.NewConfig(
+		config.WithValues(map[string]any{
+			"db.password": "secret://db_password",
+		}),
+		config.WithSecretProviders(config.NewFileSecretProvider(t.TempDir())),
+	)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "key db.password: secret db_password: secret not found")
+}
+
+func TestSecretsInListsAndMaps(t *testing.T) {
+	secretsDir := t.TempDir()
+	writeFile(t, filepath.Join(secretsDir, "first_token"), "first-token")
+	writeFile(t, filepath.Join(secretsDir, "second_token"), "second-token")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "api.yaml"), `
+api:
+  tokens:
+    - secret://first_token
+    - plain-token
+  clients:
+    - name: first
+      auth:
+        token: secret://second_token
+  urls:
+    - https://example.com
+`)
+
+	cfg, err := config.NewConfig(
+		config.WithDirectory(dir),
+		config.WithSecretProviders(config.NewFileSecretProvider(secretsDir)),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first-token", "plain-token"}, cfg.GetStringSlice("api.tokens"))
+	assert.Equal(t, []any{map[string]any{"name": "first", "auth": map[string]any{"token": "second-token"}}}, cfg.Get("api.clients"))
+	assert.True(t, cfg.IsSecret("api.tokens"))
+	assert.True(t, cfg.IsSecret("api.clients"))
+	assert.False(t, cfg.IsSecret("api.urls"))
+
+	var buf strings.Builder
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("config", "config", cfg)
+	assert.NotContains(t, buf.String(), "first-token")
+	assert.NotContains(t, buf.String(), "second-token")
+	assert.Contains(t, buf.String(), "https://example.com")
+}
+
+func TestRedactedReplicas(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "db.yaml"), `
+db:
+  replicas:
+    - user:replica-password@tcp(replica)/app
+  connections:
+    analytics:
+      replicas:
+        - user:analytics-password@tcp(analytics-replica)/analytics
+`)
+
+	cfg, err := config.NewConfig(config.WithDirectory(dir))
+	require.NoError(t, err)
+
+	for _, setting := range cfg.Settings("db") {
+		assert.Equal(t, config.Redacted, setting.Value, setting.Key)
+	}
+
+	var buf strings.Builder
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("config", "config", cfg)
+	assert.NotContains(t, buf.String(), "replica-password")
+	assert.NotContains(t, buf.String(), "analytics-password")
+}
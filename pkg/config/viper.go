@@ -0,0 +1,93 @@
+package config
+
+import "time"
+
+// The following accessors read from the current viper snapshot, so they are safe to use while the config reloads.
+
+func (c *Config) Get(key string) any {
+	return c.Viper().Get(key)
+}
+
+func (c *Config) IsSet(key string) bool {
+	return c.Viper().IsSet(key)
+}
+
+func (c *Config) AllKeys() []string {
+	return c.Viper().AllKeys()
+}
+
+func (c *Config) AllSettings() map[string]any {
+	return c.Viper().AllSettings()
+}
+
+func (c *Config) UnmarshalKey(key string, rawVal any) error {
+	return c.Viper().UnmarshalKey(key, rawVal)
+}
+
+func (c *Config) GetString(key string) string {
+	return c.Viper().GetString(key)
+}
+
+func (c *Config) GetStringSlice(key string) []string {
+	return c.Viper().GetStringSlice(key)
+}
+
+func (c *Config) GetStringMap(key string) map[string]any {
+	return c.Viper().GetStringMap(key)
+}
+
+func (c *Config) GetStringMapString(key string) map[string]string {
+	return c.Viper().GetStringMapString(key)
+}
+
+func (c *Config) GetStringMapStringSlice(key string) map[string][]string {
+	return c.Viper().GetStringMapStringSlice(key)
+}
+
+func (c *Config) GetBool(key string) bool {
+	return c.Viper().GetBool(key)
+}
+
+func (c *Config) GetFloat64(key string) float64 {
+	return c.Viper().GetFloat64(key)
+}
+
+func (c *Config) GetInt(key string) int {
+	return c.Viper().GetInt(key)
+}
+
+func (c *Config) GetInt32(key string) int32 {
+	return c.Viper().GetInt32(key)
+}
+
+func (c *Config) GetInt64(key string) int64 {
+	return c.Viper().GetInt64(key)
+}
+
+func (c *Config) GetIntSlice(key string) []int {
+	return c.Viper().GetIntSlice(key)
+}
+
+func (c *Config) GetUint(key string) uint {
+	return c.Viper().GetUint(key)
+}
+
+func (c *Config) GetUint16(key string) uint16 {
+	return c.Viper().GetUint16(key)
+}
+
+func (c *Config) GetUint32(key string) uint32 {
+	return c.Viper().GetUint32(key)
+}
+
+func (c *Config) GetUint64(key string) uint64 {
+	return c.Viper().GetUint64(key)
+}
+
+func (c *Config) GetTime(key string) time.Time {
+	return c.Viper().GetTime(key)
+}
+
+func (c *Config) GetDuration(key string) time.Duration {
+	return c.Viper().GetDuration(key)
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce groups the bursts of filesystem events emitted by a single save into one reload.
+const reloadDebounce = 100 * time.Millisecond
+
+type subscription struct {
+	key string
+	fn  func(old, new any)
+}
+
+// OnChange registers a function invoked after a reload changed the value of the key.
+func (c *Config) OnChange(key string, fn func(old, new any)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptions = append(c.subscriptions, subscription{
+		key: strings.ToLower(key),
+		fn:  fn,
+	})
+}
+
+// OnReload registers a function invoked after each reload attempt, with the error if the reload was rejected.
+func (c *Config) OnReload(fn func(err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reloadHooks = append(c.reloadHooks, fn)
+}
+
+// Reload loads and validates the config again, and swaps it in place of the current one on success.
+// On failure, the current config is left untouched.
+func (c *Config) Reload() error {
+	err := c.reload()
+
+	c.mu.Lock()
+	hooks := slices.Clone(c.reloadHooks)
+	c.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(err)
+	}
+
+	return err
+}
+
+func (c *Config) reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	snap, err := load(c.options)
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	candidate := &Config{
+		options: c.options,
+	}
+
+	candidate.snapshot.Store(snap)
+
+	if err = validate(candidate, c.options.validators); err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	old := c.snapshot.Swap(snap)
+
+	c.mu.Lock()
+	subscriptions := slices.Clone(c.subscriptions)
+	c.mu.Unlock()
+
+	for _, sub := range subscriptions {
+		oldValue, newValue := old.viper.Get(sub.key), snap.viper.Get(sub.key)
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			sub.fn(oldValue, newValue)
+		}
+	}
+
+	return nil
+}
+
+type watchTarget struct {
+	dir      string
+	file     string
+	realPath string
+}
+
+// Watch starts watching the local directories and the overlay if the watch mode is enabled, and reloads the config on change.
+func (c *Config) Watch() error {
+	if !c.options.watch {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.watcher != nil {
+		return nil
+	}
+
+	targets, err := c.watchTargets()
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	for _, target := range targets {
+		if err = watcher.Add(target.dir); err != nil {
+			return errors.Join(fmt.Errorf("failed to watch config %s: %w", target.dir, err), watcher.Close())
+		}
+	}
+
+	c.watcher = watcher
+
+	go c.watch(watcher, targets)
+
+	return nil
+}
+
+func (c *Config) watchTargets() ([]*watchTarget, error) {
+	var targets []*watchTarget
+
+	// Local directories, with their environment subdirectory when it exists
+	for _, src := range c.options.sources {
+		if src.path == "" {
+			continue
+		}
+
+		dirs := []string{src.path}
+		for _, env := range c.Envs() {
+			dirs = append(dirs, filepath.Join(src.path, env))
+		}
+
+		for _, dir := range dirs {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				targets = append(targets, &watchTarget{dir: filepath.Clean(dir)})
+			}
+		}
+	}
+
+	if c.options.overlay != "" {
+		path := filepath.Clean(c.options.overlay)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat watched config %s: %w", path, err)
+		}
+
+		// Files are watched through their parent directory, to survive editors and Kubernetes atomic replacements
+		if info.IsDir() {
+			targets = append(targets, &watchTarget{dir: path})
+		} else {
+			realPath, _ := filepath.EvalSymlinks(path)
+
+			targets = append(targets, &watchTarget{dir: filepath.Dir(path), file: path, realPath: realPath})
+		}
+	}
+
+	return targets, nil
+}
+
+// Close stops watching the config.
+func (c *Config) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.watcher == nil {
+		return nil
+	}
+
+	err := c.watcher.Close()
+	c.watcher = nil
+
+	return err
+}
+
+func (c *Config) watch(watcher *fsnotify.Watcher, targets []*watchTarget) {
+	var timer *time.Timer
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				if timer != nil {
+					timer.Stop()
+				}
+
+				return
+			}
+
+			if event.Has(fsnotify.Chmod) || !relevant(event, targets) {
+				continue
+			}
+
+			if timer == nil {
+				timer = time.AfterFunc(reloadDebounce, func() {
+					_ = c.Reload()
+				})
+			} else {
+				timer.Reset(reloadDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			c.mu.Lock()
+			hooks := slices.Clone(c.reloadHooks)
+			c.mu.Unlock()
+
+			for _, hook := range hooks {
+				hook(fmt.Errorf("config watcher error: %w", err))
+			}
+		}
+	}
+}
+
+func relevant(event fsnotify.Event, targets []*watchTarget) bool {
+	name := filepath.Clean(event.Name)
+
+	for _, target := range targets {
+		if filepath.Dir(name) != target.dir {
+			continue
+		}
+
+		if target.file == "" {
+			return true
+		}
+
+		// For a single file, only react to its own events or to a change of its symlink target
+		realPath, _ := filepath.EvalSymlinks(target.file)
+		if name == target.file || realPath != target.realPath {
+			target.realPath = realPath
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func validate(cfg *Config, validators []Validator) error {
+	var errs []error
+
+	for _, validator := range validators {
+		if err := validator(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
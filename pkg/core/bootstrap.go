@@ -2,9 +2,12 @@ package core
 
 import (
 	"context"
+	"os"
+	"syscall"
 	"testing"
 
 	config2 "github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxtest"
 )
@@ -54,10 +57,45 @@ func (b *Bootstrapper) BootstrapTestApp(tb testing.TB, options ...fx.Option) *fx
 	)
 }
 
+// RunApp starts the app within the lifecycle start timeout, waits for a shutdown signal, and stops it within the lifecycle stop timeout.
+// On SIGTERM, the stop waits for the lifecycle pre stop delay once the app is unready, before draining its connections.
 func (b *Bootstrapper) RunApp(options ...fx.Option) {
-	app := b.BootstrapApp(options...)
+	if code := b.runApp(options...); code != 0 {
+		os.Exit(code)
+	}
+}
+
+func (b *Bootstrapper) runApp(options ...fx.Option) int {
+	var manager *lifecycle.Manager
+
+	// the start and stop errors are logged by fx
+	app := b.BootstrapApp(fx.Options(options...), fx.Populate(&manager))
+	if app.Err() != nil {
+		return 1
+	}
+
+	settings := manager.Settings()
+
+	startCtx, startCancel := context.WithTimeout(b.context, settings.StartTimeout)
+	defer startCancel()
+
+	if err := app.Start(startCtx); err != nil {
+		return 1
+	}
+
+	signal := <-app.Wait()
+	if signal.Signal == syscall.SIGTERM {
+		manager.DelayStop(settings.PreStopDelay)
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(context.WithoutCancel(b.context), settings.StopTimeout)
+	defer stopCancel()
+
+	if err := app.Stop(stopCtx); err != nil {
+		return 1
+	}
 
-	app.Run()
+	return signal.ExitCode
 }
 
 func (b *Bootstrapper) RunTestApp(tb testing.TB, options ...fx.Option) func() {
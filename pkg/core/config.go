@@ -0,0 +1,24 @@
+package core
+
+import (
+	"log/slog"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"go.uber.org/fx"
+)
+
+func ConfigureConfig() fx.Option {
+	return fx.Invoke(
+		func(config *config.Config, logger *slog.Logger) {
+			config.OnReload(func(err error) {
+				if err != nil {
+					logger.Error("config reload rejected, keeping previous config", "error", err)
+
+					return
+				}
+
+				logger.Info("config reloaded")
+			})
+		},
+	)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"github.com/go-oryn/oryn-sandbox/pkg/otel"
 	"go.uber.org/fx"
 )
@@ -12,8 +13,10 @@ var Module = fx.Module(
 	ModuleName,
 	// sub modules
 	config.Module,
+	lifecycle.Module,
 	otel.Module,
 	// configurations
 	//ConfigureFx(),
+	ConfigureConfig(),
 	ConfigureOTel(),
 )
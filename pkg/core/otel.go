@@ -236,8 +236,14 @@ type ConfigureOTelLoggerHandlerParams struct {
 }
 
 func ConfigureOTelLoggerHandler(params ConfigureOTelLoggerHandlerParams) *otellog.LeveledHandler {
-	return otellog.NewLeveledHandler(
+	handler := otellog.NewLeveledHandler(
 		otellog.ParseLogLevel(params.Config.GetString("log.level")),
 		params.Handler,
 	)
+
+	params.Config.OnChange("log.level", func(_, _ any) {
+		handler.SetLevel(otellog.ParseLogLevel(params.Config.GetString("log.level")))
+	})
+
+	return handler
 }
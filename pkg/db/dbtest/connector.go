@@ -0,0 +1,240 @@
+// Package dbtest provides a fake database/sql connector, recording the statements run by the tests.
+package dbtest
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+var _ driver.Connector = (*Connector)(nil)
+
+// Statement is a recorded statement, with its whitespace collapsed.
+type Statement struct {
+	Query string
+	Args  []driver.Value
+}
+
+type ConnectorOptions struct {
+	affected int64
+	failures map[string]error
+	results  []result
+}
+
+type ConnectorOption func(*ConnectorOptions)
+
+// WithAffected sets the rows count affected by the exec statements, 1 by default.
+func WithAffected(affected int64) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.affected = affected
+	}
+}
+
+// WithFailures fails the statements once with their error, by query, including BEGIN, COMMIT, ROLLBACK and PING.
+func WithFailures(failures map[string]error) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.failures = failures
+	}
+}
+
+// WithRows returns the rows to the next query starting with the prefix, the others returning no rows.
+// Each call adds a result, returned once in turn.
+func WithRows(prefix string, columns []string, rows ...[]driver.Value) ConnectorOption {
+	return func(o *ConnectorOptions) {
+		o.results = append(o.results, result{prefix: prefix, columns: columns, rows: rows})
+	}
+}
+
+type result struct {
+	prefix  string
+	columns []string
+	rows    [][]driver.Value
+}
+
+// Connector records the statements run on its connections, and responds to them as configured.
+type Connector struct {
+	mu         sync.Mutex
+	options    ConnectorOptions
+	statements []Statement
+	execs      []Statement
+	queries    []Statement
+}
+
+func NewConnector(options ...ConnectorOption) *Connector {
+	connectorOptions := ConnectorOptions{
+		affected: 1,
+	}
+
+	for _, opt := range options {
+		opt(&connectorOptions)
+	}
+
+	return &Connector{
+		options: connectorOptions,
+	}
+}
+
+func (c *Connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{connector: c}, nil
+}
+
+func (c *Connector) Driver() driver.Driver {
+	return nil
+}
+
+// Statements returns the queries of all the recorded statements, in order.
+func (c *Connector) Statements() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queries := make([]string, 0, len(c.statements))
+	for _, statement := range c.statements {
+		queries = append(queries, statement.Query)
+	}
+
+	return queries
+}
+
+// Execs returns the recorded exec statements, in order.
+func (c *Connector) Execs() []Statement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Statement(nil), c.execs...)
+}
+
+// Queries returns the recorded query statements, in order.
+func (c *Connector) Queries() []Statement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Statement(nil), c.queries...)
+}
+
+func (c *Connector) record(query string, args []driver.NamedValue) (Statement, error) {
+	statement := Statement{Query: strings.Join(strings.Fields(query), " ")}
+	for _, arg := range args {
+		statement.Args = append(statement.Args, arg.Value)
+	}
+
+	c.statements = append(c.statements, statement)
+
+	err := c.options.failures[statement.Query]
+	delete(c.options.failures, statement.Query)
+
+	return statement, err
+}
+
+func (c *Connector) recordTx(query string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.record(query, nil)
+
+	return err
+}
+
+type conn struct {
+	connector *Connector
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c, c.connector.recordTx("BEGIN")
+}
+
+func (c *conn) Commit() error {
+	return c.connector.recordTx("COMMIT")
+}
+
+func (c *conn) Rollback() error {
+	return c.connector.recordTx("ROLLBACK")
+}
+
+func (c *conn) Ping(context.Context) error {
+	return c.connector.recordTx("PING")
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.connector.mu.Lock()
+	defer c.connector.mu.Unlock()
+
+	statement, err := c.connector.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	c.connector.execs = append(c.connector.execs, statement)
+
+	return execResult{id: int64(len(c.connector.execs)), affected: c.connector.options.affected}, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.connector.mu.Lock()
+	defer c.connector.mu.Unlock()
+
+	statement, err := c.connector.record(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	c.connector.queries = append(c.connector.queries, statement)
+
+	results := c.connector.options.results
+	for i, res := range results {
+		if strings.HasPrefix(statement.Query, res.prefix) {
+			c.connector.options.results = append(results[:i:i], results[i+1:]...)
+
+			return &rows{columns: res.columns, rows: res.rows}, nil
+		}
+	}
+
+	return &rows{}, nil
+}
+
+type execResult struct {
+	id       int64
+	affected int64
+}
+
+func (r execResult) LastInsertId() (int64, error) {
+	return r.id, nil
+}
+
+func (r execResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+type rows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
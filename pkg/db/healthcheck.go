@@ -3,34 +3,113 @@ package db
 import (
 	"context"
 	"database/sql"
-	"log/slog"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"go.uber.org/fx"
 )
 
+// DBProbe pings a db, and expects its connection pool not to be saturated:
+// at most a max usage ratio of its max open connections in use, if limited.
 type DBProbe struct {
-	logger *slog.Logger
-	db     *sql.DB
+	name         string
+	db           *sql.DB
+	maxPoolUsage float64
 }
 
-func NewDBProbe(logger *slog.Logger, db *sql.DB) *DBProbe {
-	return &DBProbe{
-		logger: logger,
-		db:     db,
+type DBProbeOption func(p *DBProbe)
+
+// WithMaxPoolUsage sets the max ratio of the max open connections in use, 1 by default.
+func WithMaxPoolUsage(ratio float64) DBProbeOption {
+	return func(p *DBProbe) {
+		p.maxPoolUsage = ratio
 	}
 }
 
+func NewDBProbe(db *sql.DB, options ...DBProbeOption) *DBProbe {
+	return NewNamedDBProbe("db", db, options...)
+}
+
+// NewNamedDBProbe returns a DBProbe with a name, for the named connections.
+func NewNamedDBProbe(name string, db *sql.DB, options ...DBProbeOption) *DBProbe {
+	probe := &DBProbe{
+		name:         name,
+		db:           db,
+		maxPoolUsage: 1,
+	}
+
+	for _, opt := range options {
+		opt(probe)
+	}
+
+	return probe
+}
+
 func (p *DBProbe) Name() string {
-	return "db"
+	return p.name
+}
+
+func (p *DBProbe) ComponentType() string {
+	return "datastore"
 }
 
 func (p *DBProbe) Probe(ctx context.Context) error {
 	err := p.db.PingContext(ctx)
 	if err != nil {
-		msg := "database ping error"
+		return fmt.Errorf("database ping error: %w", err)
+	}
 
-		p.logger.ErrorContext(ctx, msg, "error", err)
+	stats := p.db.Stats()
+	if stats.MaxOpenConnections <= 0 {
+		return nil
+	}
 
-		return err
+	if usage := float64(stats.InUse) / float64(stats.MaxOpenConnections); usage > p.maxPoolUsage {
+		return fmt.Errorf(
+			"database pool is saturated: %d of %d connections in use, %d waits",
+			stats.InUse,
+			stats.MaxOpenConnections,
+			stats.WaitCount,
+		)
 	}
 
 	return nil
 }
+
+type ProvideConfiguredProbesParams struct {
+	fx.In
+	Config *config.Config
+	DB     *sql.DB
+}
+
+// ProvideConfiguredProbes provides the probes of type db configured under healthcheck.probes, on the default connection.
+func ProvideConfiguredProbes(params ProvideConfiguredProbesParams) ([]healthcheck.ProbeRegistration, error) {
+	settings, err := config.Bind[healthcheck.Settings](params.Config, healthcheck.ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	var registrations []healthcheck.ProbeRegistration
+
+	for _, name := range slices.Sorted(maps.Keys(settings.Probes)) {
+		probeSettings := settings.Probes[name]
+		if probeSettings.Type != "db" {
+			continue
+		}
+
+		maxPoolUsage := probeSettings.MaxPoolUsage
+		if maxPoolUsage == 0 {
+			maxPoolUsage = 1
+		}
+
+		registrations = append(registrations, healthcheck.ProbeRegistration{
+			Probe:   NewNamedDBProbe(name, params.DB, WithMaxPoolUsage(maxPoolUsage)),
+			Options: probeSettings.Options(),
+		})
+	}
+
+	return registrations, nil
+}
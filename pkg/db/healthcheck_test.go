@@ -0,0 +1,33 @@
+package db_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvideConfiguredProbes(t *testing.T) {
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"healthcheck.probes.db-pool.type":              "db",
+		"healthcheck.probes.db-pool.max_pool_usage":    0.8,
+		"healthcheck.probes.goroutines.type":           "goroutines",
+		"healthcheck.probes.goroutines.max_goroutines": 100,
+	}))
+	require.NoError(t, err)
+
+	registrations, err := db.ProvideConfiguredProbes(db.ProvideConfiguredProbesParams{
+		Config: cfg,
+		DB:     sql.OpenDB(dbtest.NewConnector()),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "db-pool", registrations[0].Probe.Name())
+	assert.Implements(t, (*healthcheck.ComponentProbe)(nil), registrations[0].Probe)
+}
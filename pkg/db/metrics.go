@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// poolMetrics reports the connection pool stats of a db.
+type poolMetrics struct {
+	name           string
+	db             *sql.DB
+	connections    metric.Int64ObservableGauge
+	maxConnections metric.Int64ObservableGauge
+	waitCount      metric.Int64ObservableGauge
+	waitDuration   metric.Float64ObservableGauge
+}
+
+// RegisterPoolMetrics registers the connection pool metrics of a db, until unregistered.
+func RegisterPoolMetrics(meter metric.Meter, name string, db *sql.DB) (metric.Registration, error) {
+	connections, err := meter.Int64ObservableGauge(
+		"db.client.connection.count",
+		metric.WithDescription("Number of connections of the pool, by state: used or idle."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConnections, err := meter.Int64ObservableGauge(
+		"db.client.connection.max",
+		metric.WithDescription("Maximum number of open connections of the pool, 0 if unlimited."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	waitCount, err := meter.Int64ObservableGauge(
+		"db.client.connection.wait.count",
+		metric.WithDescription("Total number of connections waited for."),
+		metric.WithUnit("{wait}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	waitDuration, err := meter.Float64ObservableGauge(
+		"db.client.connection.wait.duration",
+		metric.WithDescription("Total time waited for new connections."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &poolMetrics{
+		name:           name,
+		db:             db,
+		connections:    connections,
+		maxConnections: maxConnections,
+		waitCount:      waitCount,
+		waitDuration:   waitDuration,
+	}
+
+	return meter.RegisterCallback(metrics.observe, connections, maxConnections, waitCount, waitDuration)
+}
+
+// observe reports the pool stats.
+func (m *poolMetrics) observe(_ context.Context, observer metric.Observer) error {
+	stats := m.db.Stats()
+	pool := semconv.DBClientConnectionPoolName(m.name)
+
+	observer.ObserveInt64(m.connections, int64(stats.InUse), metric.WithAttributes(pool, semconv.DBClientConnectionStateUsed))
+	observer.ObserveInt64(m.connections, int64(stats.Idle), metric.WithAttributes(pool, semconv.DBClientConnectionStateIdle))
+	observer.ObserveInt64(m.maxConnections, int64(stats.MaxOpenConnections), metric.WithAttributes(pool))
+	observer.ObserveInt64(m.waitCount, stats.WaitCount, metric.WithAttributes(pool))
+	observer.ObserveFloat64(m.waitDuration, stats.WaitDuration.Seconds(), metric.WithAttributes(pool))
+
+	return nil
+}
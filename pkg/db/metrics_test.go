@@ -0,0 +1,57 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRegisterPoolMetrics(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+
+	pool := sql.OpenDB(dbtest.NewConnector())
+	pool.SetMaxOpenConns(1)
+
+	registration, err := db.RegisterPoolMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"), "default", pool)
+	require.NoError(t, err)
+
+	// wait for the single connection
+	conn, err := pool.Conn(ctx)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, conn.Close())
+	}()
+
+	waiting, err := pool.Conn(ctx)
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	// used and idle connections
+	connections := metrics["db.client.connection.count"].(metricdata.Gauge[int64])
+	assert.Len(t, connections.DataPoints, 2)
+
+	assert.Equal(t, int64(1), metrics["db.client.connection.max"].(metricdata.Gauge[int64]).DataPoints[0].Value)
+	assert.Equal(t, int64(1), metrics["db.client.connection.wait.count"].(metricdata.Gauge[int64]).DataPoints[0].Value)
+	assert.Greater(t, metrics["db.client.connection.wait.duration"].(metricdata.Gauge[float64]).DataPoints[0].Value, 0.01)
+
+	require.NoError(t, waiting.Close())
+	require.NoError(t, registration.Unregister())
+}
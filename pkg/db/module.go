@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/XSAM/otelsql"
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	_ "github.com/go-sql-driver/mysql"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
@@ -20,17 +23,31 @@ const ModuleName = "db"
 
 var Module = fx.Module(
 	ModuleName,
+	// config
+	config.AsSchema[Settings](ModuleName),
+	// default connection replicas probes
+	healthcheck.AsProbeRegistrations(func(router *Router) []healthcheck.ProbeRegistration {
+		return router.ProbeRegistrations()
+	}),
+	// db probes configured under healthcheck.probes
+	healthcheck.AsProbeRegistrations(ProvideConfiguredProbes),
 	// dependencies
 	fx.Provide(
 		ProvideDB,
+		ProvideRouter,
+		ProvideTransactor,
 		ProvideMigrator,
 		ProvideSeeder,
 	),
 )
 
+// DefaultConnection is the name of the connection configured by db.driver and db.dsn.
+const DefaultConnection = "default"
+
 type ProvideDBParams struct {
 	fx.In
 	Lifecycle      fx.Lifecycle
+	Manager        *lifecycle.Manager
 	Shutdown       fx.Shutdowner
 	Config         *config.Config
 	Logger         *slog.Logger
@@ -40,19 +57,74 @@ type ProvideDBParams struct {
 }
 
 func ProvideDB(params ProvideDBParams) (*sql.DB, error) {
-	driver := params.Config.GetString("db.driver")
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return openDB(params, settings.Pool, DefaultConnection, settings.Driver, settings.DSN)
+}
+
+type ProvideRouterParams struct {
+	fx.In
+	ProvideDBParams
+	DB *sql.DB
+}
+
+// ProvideRouter provides the default connection Router, with the db.replicas.
+func ProvideRouter(params ProvideRouterParams) (*Router, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRouter(params.ProvideDBParams, settings, DefaultConnection, params.DB, settings.Driver, settings.Replicas)
+}
+
+// ProvideConnection provides the named connection configured under db.connections.<name>, and its Router.
+func ProvideConnection(params ProvideDBParams, name string) (*sql.DB, *Router, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connectionSettings, ok := settings.Connections[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("db connection %s is not configured", name)
+	}
+
+	driver := connectionSettings.Driver
+	if driver == "" {
+		driver = settings.Driver
+	}
+
+	db, err := openDB(params, settings.Pool, name, driver, connectionSettings.DSN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	router, err := newRouter(params, settings, name, db, driver, connectionSettings.Replicas)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return db, router, nil
+}
+
+// openDB opens an instrumented connections pool, identified by its name in its telemetry, and closed on stop.
+func openDB(params ProvideDBParams, pool PoolSettings, name string, driver string, dsn string) (*sql.DB, error) {
 	if driver == "" {
-		return nil, errors.New("db driver is not configured")
+		return nil, fmt.Errorf("db %s driver is not configured", name)
 	}
 
-	dsn := params.Config.GetString("db.dsn")
 	if dsn == "" {
-		return nil, errors.New("db dsn is not configured")
+		return nil, fmt.Errorf("db %s dsn is not configured", name)
 	}
 
 	attrs := append(
-		otelsql.AttributesFromDSN(params.Config.GetString("db.dsn")),
+		otelsql.AttributesFromDSN(dsn),
 		semconv.DBSystemNameMySQL,
+		semconv.DBClientConnectionPoolName(name),
 	)
 
 	db, err := otelsql.Open(
@@ -64,18 +136,98 @@ func ProvideDB(params ProvideDBParams) (*sql.DB, error) {
 		otelsql.WithTracerProvider(params.TracerProvider),
 	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to open db %s: %w", name, err)
 	}
 
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	registration, err := RegisterPoolMetrics(params.MeterProvider.Meter("github.com/go-oryn/oryn-sandbox/pkg/db"), name, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register db %s pool metrics: %w", name, err)
+	}
+
+	watcher := NewPoolWatcher(params.Logger, name, db, pool.WaitThreshold, pool.WaitCheckInterval)
+
 	params.Lifecycle.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			return db.Close()
+		OnStart: func(context.Context) error {
+			watcher.Start()
+
+			return nil
 		},
 	})
 
+	params.Manager.OnStop(lifecycle.PhaseWorkers, "db "+name+" pool watcher", func(context.Context) error {
+		watcher.Stop()
+
+		return nil
+	})
+
+	params.Manager.OnStop(lifecycle.PhaseClose, "db "+name, func(context.Context) error {
+		return errors.Join(registration.Unregister(), db.Close())
+	})
+
 	return db, nil
 }
 
+// newRouter opens the replicas of a connection, and checks their health while the app runs.
+func newRouter(
+	params ProvideDBParams,
+	settings Settings,
+	name string,
+	primary *sql.DB,
+	driver string,
+	replicaDSNs []string,
+) (*Router, error) {
+	replicas := make(map[string]*sql.DB, len(replicaDSNs))
+	for i, dsn := range replicaDSNs {
+		replicaName := fmt.Sprintf("%s-replica-%d", name, i+1)
+
+		replica, err := openDB(params, settings.Pool, replicaName, driver, dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		replicas[replicaName] = replica
+	}
+
+	router := NewRouter(params.Logger, primary, replicas, settings.ReplicaCheckInterval)
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			router.Start()
+
+			return nil
+		},
+	})
+
+	params.Manager.OnStop(lifecycle.PhaseWorkers, "db "+name+" replicas checks", func(context.Context) error {
+		router.Stop()
+
+		return nil
+	})
+
+	return router, nil
+}
+
+type ProvideTransactorParams struct {
+	fx.In
+	Config *config.Config
+	Logger *slog.Logger
+	DB     *sql.DB
+}
+
+func ProvideTransactor(params ProvideTransactorParams) (*Transactor, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTransactor(params.DB, params.Logger, settings.Tx), nil
+}
+
 type ProvideMigratorParams struct {
 	fx.In
 	Config  *config.Config
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// PoolWatcher warns when the time waited for the connections of a db, during a check interval, crosses the wait threshold.
+type PoolWatcher struct {
+	logger    *slog.Logger
+	name      string
+	db        *sql.DB
+	threshold time.Duration
+	interval  time.Duration
+	last      sql.DBStats
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewPoolWatcher(logger *slog.Logger, name string, db *sql.DB, threshold time.Duration, interval time.Duration) *PoolWatcher {
+	return &PoolWatcher{
+		logger:    logger,
+		name:      name,
+		db:        db,
+		threshold: threshold,
+		interval:  interval,
+	}
+}
+
+// Start checks the pool wait time in background on the check interval, it does nothing if no threshold is configured.
+func (w *PoolWatcher) Start() {
+	if w.threshold <= 0 || w.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.last = w.db.Stats()
+
+	w.wg.Go(func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.check(ctx)
+			}
+		}
+	})
+}
+
+func (w *PoolWatcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+		w.wg.Wait()
+	}
+}
+
+func (w *PoolWatcher) check(ctx context.Context) {
+	stats := w.db.Stats()
+	last := w.last
+	w.last = stats
+
+	waited := stats.WaitDuration - last.WaitDuration
+	if waited <= w.threshold {
+		return
+	}
+
+	w.logger.WarnContext(
+		ctx,
+		"db connection pool wait time crossed the threshold, consider raising db.pool.max_open_conns",
+		"pool", w.name,
+		"wait_duration", waited,
+		"wait_count", stats.WaitCount-last.WaitCount,
+		"threshold", w.threshold,
+		"interval", w.interval,
+		"in_use", stats.InUse,
+		"max_open_conns", stats.MaxOpenConnections,
+	)
+}
@@ -0,0 +1,73 @@
+package db_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestPoolWatcher(t *testing.T) {
+	ctx := context.Background()
+	logs := &syncBuffer{}
+
+	pool := sql.OpenDB(dbtest.NewConnector())
+	pool.SetMaxOpenConns(1)
+
+	// without any metrics collection
+	watcher := db.NewPoolWatcher(slog.New(slog.NewTextHandler(logs, nil)), "default", pool, 10*time.Millisecond, 100*time.Millisecond)
+	watcher.Start()
+	defer watcher.Stop()
+
+	// wait for the single connection
+	conn, err := pool.Conn(ctx)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, conn.Close())
+	}()
+
+	waiting, err := pool.Conn(ctx)
+	require.NoError(t, err)
+	require.NoError(t, waiting.Close())
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "db connection pool wait time crossed the threshold")
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, logs.String(), "pool=default")
+
+	// no more waits during the next intervals
+	time.Sleep(50 * time.Millisecond)
+	warnings := logs.String()
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, warnings, logs.String())
+}
@@ -1,9 +1,58 @@
 package db
 
 import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
 	"go.uber.org/fx"
 )
 
+// AsConnection registers the named connection configured under db.connections.<name>, with its health check probes.
+// Its *sql.DB, *Router and *Transactor are injected with the name tag, for example `name:"analytics"`.
+func AsConnection(name string) fx.Option {
+	tag := fmt.Sprintf(`name:"%s"`, name)
+
+	return fx.Options(
+		fx.Provide(
+			fx.Annotate(
+				func(params ProvideDBParams) (*sql.DB, *Router, error) {
+					return ProvideConnection(params, name)
+				},
+				fx.ResultTags(tag, tag),
+			),
+			fx.Annotate(
+				func(cfg *config.Config, logger *slog.Logger, db *sql.DB) (*Transactor, error) {
+					settings, err := config.Bind[Settings](cfg, ModuleName)
+					if err != nil {
+						return nil, err
+					}
+
+					return NewTransactor(db, logger, settings.Tx), nil
+				},
+				fx.ParamTags("", "", tag),
+				fx.ResultTags(tag),
+			),
+		),
+		healthcheck.AsProbeRegistrations(
+			fx.Annotate(
+				func(db *sql.DB, router *Router) []healthcheck.ProbeRegistration {
+					return append(
+						router.ProbeRegistrations(),
+						healthcheck.ProbeRegistration{
+							Probe:   NewNamedDBProbe("db-"+name, db),
+							Options: []healthcheck.ProbeOption{healthcheck.Readiness, healthcheck.Startup},
+						},
+					)
+				},
+				fx.ParamTags(tag, tag),
+			),
+		),
+	)
+}
+
 func AsMigratorOptions(options ...MigratorOption) fx.Option {
 	fxOptions := []fx.Option{}
 
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+)
+
+type routeContextKey struct{}
+
+type route int
+
+const (
+	routePrimary route = iota + 1
+	routeReplica
+)
+
+// ContextWithPrimary routes the queries of the context to the primary, for example to read its own writes.
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, routePrimary)
+}
+
+// ContextWithReplica routes the queries of the context to the replicas, even if not detected as read-only.
+func ContextWithReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, routeReplica)
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+var _ Querier = (*Router)(nil)
+
+// Router is a Querier sending the read-only queries, or the queries of the contexts marked with ContextWithReplica,
+// to the healthy replicas in turn, and the other ones to the primary (or to the transaction of the context).
+// The replicas health is checked every check interval, the primary is used when none is healthy.
+type Router struct {
+	logger   *slog.Logger
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewRouter returns a Router, with its replicas by name.
+func NewRouter(logger *slog.Logger, primary *sql.DB, replicas map[string]*sql.DB, interval time.Duration) *Router {
+	router := &Router{
+		logger:   logger,
+		primary:  primary,
+		interval: interval,
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(replicas)) {
+		r := &replica{name: name, db: replicas[name]}
+		r.healthy.Store(true)
+
+		router.replicas = append(router.replicas, r)
+	}
+
+	return router
+}
+
+// ProbeRegistrations returns the replicas health check probes registrations,
+// not critical since the router falls back to the primary.
+func (r *Router) ProbeRegistrations() []healthcheck.ProbeRegistration {
+	registrations := make([]healthcheck.ProbeRegistration, 0, len(r.replicas))
+	for _, replica := range r.replicas {
+		registrations = append(registrations, healthcheck.ProbeRegistration{
+			Probe:   NewNamedDBProbe("db-"+replica.name, replica.db),
+			Options: []healthcheck.ProbeOption{healthcheck.Readiness, healthcheck.NonCritical()},
+		})
+	}
+
+	return registrations
+}
+
+func (r *Router) Primary() *sql.DB {
+	return r.primary
+}
+
+func (r *Router) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return QuerierFromContext(ctx, r.primary).ExecContext(ctx, query, args...)
+}
+
+func (r *Router) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.reader(ctx, query).QueryContext(ctx, query, args...)
+}
+
+func (r *Router) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return r.reader(ctx, query).QueryRowContext(ctx, query, args...)
+}
+
+func (r *Router) reader(ctx context.Context, query string) Querier {
+	if tx, ok := TxFromContext(ctx, r.primary); ok {
+		return tx
+	}
+
+	switch ctx.Value(routeContextKey{}) {
+	case routePrimary:
+		return r.primary
+	case routeReplica:
+	default:
+		if !readOnly(query) {
+			return r.primary
+		}
+	}
+
+	// round-robin on the healthy replicas
+	start := r.next.Add(1)
+	for i := range uint64(len(r.replicas)) {
+		candidate := r.replicas[(start+i)%uint64(len(r.replicas))]
+		if candidate.healthy.Load() {
+			return candidate.db
+		}
+	}
+
+	return r.primary
+}
+
+// readOnly returns true for the queries without side effects, and not locking rows.
+func readOnly(query string) bool {
+	normalized := strings.ToUpper(strings.TrimLeft(query, " \t\r\n("))
+
+	for _, prefix := range []string{"SELECT", "SHOW", "DESCRIBE", "EXPLAIN"} {
+		if strings.HasPrefix(normalized, prefix) {
+			return !strings.Contains(normalized, "FOR UPDATE") &&
+				!strings.Contains(normalized, "FOR SHARE") &&
+				!strings.Contains(normalized, "LOCK IN SHARE MODE")
+		}
+	}
+
+	return false
+}
+
+// Start checks the replicas health once, then every check interval until stopped.
+func (r *Router) Start() {
+	if len(r.replicas) == 0 || r.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.check(ctx)
+
+	r.wg.Go(func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.check(ctx)
+			}
+		}
+	})
+}
+
+func (r *Router) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+}
+
+func (r *Router) check(ctx context.Context) {
+	for _, replica := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, r.interval)
+		err := replica.db.PingContext(pingCtx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) == healthy {
+			continue
+		}
+
+		if healthy {
+			r.logger.InfoContext(ctx, "db replica is healthy again", "replica", replica.name)
+		} else {
+			r.logger.WarnContext(ctx, "db replica is unhealthy, falling back to the primary", "replica", replica.name, "error", err)
+		}
+	}
+}
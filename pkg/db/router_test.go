@@ -0,0 +1,90 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(replicaFailures map[string]error) (*db.Router, *dbtest.Connector, *dbtest.Connector) {
+	primary := dbtest.NewConnector()
+	replica := dbtest.NewConnector(dbtest.WithFailures(replicaFailures))
+
+	return db.NewRouter(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		sql.OpenDB(primary),
+		map[string]*sql.DB{"replica": sql.OpenDB(replica)},
+		time.Hour,
+	), primary, replica
+}
+
+func query(t *testing.T, router *db.Router, ctx context.Context, statement string) {
+	t.Helper()
+
+	rows, err := router.QueryContext(ctx, statement)
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+}
+
+func TestRouterRouting(t *testing.T) {
+	t.Parallel()
+
+	router, primary, replica := newRouter(nil)
+
+	query(t, router, t.Context(), "SELECT 1")
+	query(t, router, t.Context(), " (select 2)")
+	query(t, router, t.Context(), "SELECT 3 FOR UPDATE")
+	query(t, router, db.ContextWithPrimary(t.Context()), "SELECT 4")
+	query(t, router, db.ContextWithReplica(t.Context()), "CALL read_only()")
+
+	_, err := router.ExecContext(db.ContextWithReplica(t.Context()), "UPDATE users SET name = 'x'")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"SELECT 3 FOR UPDATE", "SELECT 4", "UPDATE users SET name = 'x'"}, primary.Statements())
+	assert.Equal(t, []string{"SELECT 1", "(select 2)", "CALL read_only()"}, replica.Statements())
+}
+
+func TestRouterTransaction(t *testing.T) {
+	t.Parallel()
+
+	router, primary, replica := newRouter(nil)
+
+	transactor := db.NewTransactor(router.Primary(), slog.New(slog.NewTextHandler(io.Discard, nil)), db.TxSettings{})
+
+	err := transactor.WithinTx(t.Context(), func(ctx context.Context) error {
+		query(t, router, ctx, "SELECT 1")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"BEGIN", "SELECT 1", "COMMIT"}, primary.Statements())
+	assert.Empty(t, replica.Statements())
+}
+
+func TestRouterFallback(t *testing.T) {
+	t.Parallel()
+
+	router, primary, replica := newRouter(map[string]error{"PING": errors.New("unreachable")})
+
+	router.Start()
+	defer router.Stop()
+
+	query(t, router, t.Context(), "SELECT 1")
+
+	assert.Equal(t, []string{"SELECT 1"}, primary.Statements())
+	assert.Equal(t, []string{"PING"}, replica.Statements())
+
+	registrations := router.ProbeRegistrations()
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "db-replica", registrations[0].Probe.Name())
+}
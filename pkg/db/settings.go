@@ -0,0 +1,41 @@
+package db
+
+import "time"
+
+// Settings configures the default connection, with its read replicas checked every replica check interval,
+// and the named connections, all with the same pool settings.
+type Settings struct {
+	Driver               string                        `mapstructure:"driver" validate:"required"`
+	DSN                  string                        `mapstructure:"dsn"`
+	Replicas             []string                      `mapstructure:"replicas"`
+	ReplicaCheckInterval time.Duration                 `mapstructure:"replica_check_interval" default:"5s" validate:"gte=0"`
+	Connections          map[string]ConnectionSettings `mapstructure:"connections" validate:"dive"`
+	Pool                 PoolSettings                  `mapstructure:"pool"`
+	Tx                   TxSettings                    `mapstructure:"tx"`
+	Seeds                map[string]any                `mapstructure:"seeds"`
+}
+
+// ConnectionSettings configures a named connection, registered with AsConnection, using the default driver if not set.
+type ConnectionSettings struct {
+	Driver   string   `mapstructure:"driver"`
+	DSN      string   `mapstructure:"dsn" validate:"required"`
+	Replicas []string `mapstructure:"replicas"`
+}
+
+// PoolSettings configures the connections pools, see the sql.DB setters, 0 meaning unlimited except for the max idle conns.
+// A warning is logged when the time waited for connections during a wait check interval crosses the wait threshold (0 to disable).
+type PoolSettings struct {
+	MaxOpenConns      int           `mapstructure:"max_open_conns" default:"25" validate:"gte=0"`
+	MaxIdleConns      int           `mapstructure:"max_idle_conns" default:"25" validate:"gte=0"`
+	ConnMaxLifetime   time.Duration `mapstructure:"conn_max_lifetime" default:"5m" validate:"gte=0"`
+	ConnMaxIdleTime   time.Duration `mapstructure:"conn_max_idle_time" default:"1m" validate:"gte=0"`
+	WaitThreshold     time.Duration `mapstructure:"wait_threshold" default:"1s" validate:"gte=0"`
+	WaitCheckInterval time.Duration `mapstructure:"wait_check_interval" default:"10s" validate:"gte=0"`
+}
+
+// TxSettings configures the retries of the transactions failing on deadlocks or lock wait timeouts,
+// delayed by the retry delay times the attempt.
+type TxSettings struct {
+	MaxRetries int           `mapstructure:"max_retries" default:"3" validate:"gte=0"`
+	RetryDelay time.Duration `mapstructure:"retry_delay" default:"50ms" validate:"gte=0"`
+}
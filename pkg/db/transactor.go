@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL deadlock and lock wait timeout errors, the transactions failing with them can be retried.
+const (
+	mysqlErrLockDeadlock    = 1213
+	mysqlErrLockWaitTimeout = 1205
+)
+
+// Querier runs queries, it is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txContextKey keys the transactions of a context by their db, so a transaction is only used for the queries of its db.
+type txContextKey struct {
+	db *sql.DB
+}
+
+type txState struct {
+	db    *sql.DB
+	tx    *sql.Tx
+	depth int
+}
+
+func txStateFromContext(ctx context.Context, db *sql.DB) (*txState, bool) {
+	state, ok := ctx.Value(txContextKey{db: db}).(*txState)
+
+	return state, ok && state.db == db
+}
+
+// TxFromContext returns the transaction of the context started on the db by Transactor.WithinTx.
+func TxFromContext(ctx context.Context, db *sql.DB) (*sql.Tx, bool) {
+	state, ok := txStateFromContext(ctx, db)
+	if !ok {
+		return nil, false
+	}
+
+	return state.tx, true
+}
+
+// QuerierFromContext returns the transaction of the context started on the db if any, or the db.
+func QuerierFromContext(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := TxFromContext(ctx, db); ok {
+		return tx
+	}
+
+	return db
+}
+
+// Transactor runs functions within transactions, propagated through their context.
+type Transactor struct {
+	db       *sql.DB
+	logger   *slog.Logger
+	settings TxSettings
+}
+
+func NewTransactor(db *sql.DB, logger *slog.Logger, settings TxSettings) *Transactor {
+	return &Transactor{
+		db:       db,
+		logger:   logger,
+		settings: settings,
+	}
+}
+
+// Querier returns the transaction of the context if any, or the db.
+func (t *Transactor) Querier(ctx context.Context) Querier {
+	return QuerierFromContext(ctx, t.db)
+}
+
+// WithinTx runs the function within a transaction, committed if it succeeds, and rolled back otherwise
+// (or on panic), returning the function error. Within a transaction, it runs the function within a savepoint instead.
+// A transaction failing on a deadlock or lock wait timeout is retried up to the configured max retries.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if state, ok := txStateFromContext(ctx, t.db); ok {
+		return t.withinSavepoint(ctx, state, fn)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := t.withinTx(ctx, fn)
+		if err == nil || !retryable(err) || attempt >= t.settings.MaxRetries {
+			return err
+		}
+
+		t.logger.WarnContext(ctx, "retrying db transaction", "error", err, "attempt", attempt+1)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(t.settings.RetryDelay * time.Duration(attempt+1)):
+		}
+	}
+}
+
+func (t *Transactor) withinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.rollback(ctx, tx.Rollback)
+
+			panic(r)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txContextKey{db: t.db}, &txState{db: t.db, tx: tx})); err != nil {
+		t.rollback(ctx, tx.Rollback)
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Transactor) withinSavepoint(ctx context.Context, state *txState, fn func(ctx context.Context) error) (err error) {
+	savepoint := fmt.Sprintf("sp_%d", state.depth+1)
+
+	if _, err = state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to create savepoint %s: %w", savepoint, err)
+	}
+
+	rollback := func() error {
+		_, err := state.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+savepoint)
+
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.rollback(ctx, rollback)
+
+			panic(r)
+		}
+	}()
+
+	next := &txState{db: state.db, tx: state.tx, depth: state.depth + 1}
+
+	if err = fn(context.WithValue(ctx, txContextKey{db: t.db}, next)); err != nil {
+		t.rollback(ctx, rollback)
+
+		return err
+	}
+
+	if _, err = state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to release savepoint %s: %w", savepoint, err)
+	}
+
+	return nil
+}
+
+// rollback rolls back, and only logs its failure so the original error is returned.
+func (t *Transactor) rollback(ctx context.Context, rollback func() error) {
+	if err := rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		t.logger.ErrorContext(ctx, "failed to rollback db transaction", "error", err)
+	}
+}
+
+func retryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrLockDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+
+	return false
+}
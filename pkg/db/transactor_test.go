@@ -0,0 +1,132 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransactor(failures map[string]error) (*db.Transactor, *dbtest.Connector) {
+	connector := dbtest.NewConnector(dbtest.WithFailures(failures))
+
+	return db.NewTransactor(
+		sql.OpenDB(connector),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		db.TxSettings{MaxRetries: 1},
+	), connector
+}
+
+func TestTransactorWithinTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commits", func(t *testing.T) {
+		transactor, connector := newTransactor(nil)
+
+		_, ok := transactor.Querier(ctx).(*sql.DB)
+		assert.True(t, ok)
+
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			_, ok := transactor.Querier(ctx).(*sql.Tx)
+			assert.True(t, ok)
+
+			_, err := transactor.Querier(ctx).ExecContext(ctx, "INSERT")
+
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"BEGIN", "INSERT", "COMMIT"}, connector.Statements())
+	})
+
+	t.Run("rolls back and returns the original error", func(t *testing.T) {
+		transactor, connector := newTransactor(map[string]error{"ROLLBACK": errors.New("rollback error")})
+
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			return errors.New("insert error")
+		})
+
+		assert.EqualError(t, err, "insert error")
+		assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, connector.Statements())
+	})
+
+	t.Run("rolls back nested transactions to their savepoint", func(t *testing.T) {
+		transactor, connector := newTransactor(nil)
+
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+				return transactor.WithinTx(ctx, func(ctx context.Context) error {
+					return errors.New("nested error")
+				})
+			})
+			assert.EqualError(t, err, "nested error")
+
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]string{"BEGIN", "SAVEPOINT sp_1", "SAVEPOINT sp_2", "ROLLBACK TO SAVEPOINT sp_2", "ROLLBACK TO SAVEPOINT sp_1", "COMMIT"},
+			connector.Statements(),
+		)
+	})
+
+	t.Run("scopes transactions to their db", func(t *testing.T) {
+		transactor, connector := newTransactor(nil)
+		other, otherConnector := newTransactor(nil)
+
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			_, ok := other.Querier(ctx).(*sql.DB)
+			assert.True(t, ok)
+
+			return other.WithinTx(ctx, func(ctx context.Context) error {
+				_, err := transactor.Querier(ctx).ExecContext(ctx, "INSERT")
+				if err != nil {
+					return err
+				}
+
+				_, err = other.Querier(ctx).ExecContext(ctx, "UPDATE")
+
+				return err
+			})
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"BEGIN", "INSERT", "COMMIT"}, connector.Statements())
+		assert.Equal(t, []string{"BEGIN", "UPDATE", "COMMIT"}, otherConnector.Statements())
+	})
+
+	t.Run("retries deadlocks", func(t *testing.T) {
+		transactor, connector := newTransactor(map[string]error{"INSERT": &mysql.MySQLError{Number: 1213, Message: "deadlock"}})
+
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := transactor.Querier(ctx).ExecContext(ctx, "INSERT")
+
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"BEGIN", "INSERT", "ROLLBACK", "BEGIN", "INSERT", "COMMIT"}, connector.Statements())
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		transactor, connector := newTransactor(nil)
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = transactor.WithinTx(ctx, func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+
+		assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, connector.Statements())
+	})
+}
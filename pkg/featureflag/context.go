@@ -0,0 +1,38 @@
+package featureflag
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// ContextWithAttributes returns a copy of ctx with the attributes added to its baggage, to evaluate flags against them.
+func ContextWithAttributes(ctx context.Context, attributes map[string]string) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+
+	for key, value := range attributes {
+		member, err := baggage.NewMemberRaw(key, value)
+		if err != nil {
+			return ctx, err
+		}
+
+		bag, err = bag.SetMember(member)
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// Attributes returns the evaluation context attributes of ctx, from its baggage.
+func Attributes(ctx context.Context) map[string]string {
+	members := baggage.FromContext(ctx).Members()
+
+	attributes := make(map[string]string, len(members))
+	for _, member := range members {
+		attributes[member.Key()] = member.Value()
+	}
+
+	return attributes
+}
@@ -0,0 +1,137 @@
+package featureflag
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Evaluation reasons.
+const (
+	ReasonUnknown  = "unknown"
+	ReasonDisabled = "disabled"
+	ReasonRule     = "targeting_match"
+	ReasonRollout  = "split"
+	ReasonStatic   = "static"
+)
+
+// Evaluation is the result of a flag evaluation.
+type Evaluation struct {
+	Flag    string
+	Enabled bool
+	Reason  string
+}
+
+type Flags struct {
+	telemetry otel.Telemetry
+	counter   metric.Int64Counter
+	flags     atomic.Pointer[map[string]FlagSettings]
+}
+
+// NewFlags returns the flags of the config, reloaded on the flags section changes.
+func NewFlags(cfg *config.Config, telemetry otel.Telemetry) (*Flags, error) {
+	counter, err := telemetry.Meter().Int64Counter(
+		"feature_flag.evaluations",
+		metric.WithDescription("Number of feature flags evaluations."),
+		metric.WithUnit("{evaluation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := LoadSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Flags{
+		telemetry: telemetry,
+		counter:   counter,
+	}
+	f.flags.Store(&settings)
+
+	cfg.OnChange(ModuleSection, func(_, _ any) {
+		settings, err := LoadSettings(cfg)
+		if err != nil {
+			telemetry.Logger().Error("cannot reload feature flags, keeping previous ones", "error", err)
+
+			return
+		}
+
+		f.flags.Store(&settings)
+	})
+
+	return f, nil
+}
+
+// Enabled evaluates a flag against the ctx attributes, see Attributes.
+func (f *Flags) Enabled(ctx context.Context, name string) bool {
+	return f.Evaluate(ctx, name).Enabled
+}
+
+// Evaluate evaluates a flag against the ctx attributes, and records the evaluation as span event and metric.
+// The flags names are case-insensitive, since the config keys are lowercased: the evaluation flag is the lowercased name.
+func (f *Flags) Evaluate(ctx context.Context, name string) Evaluation {
+	name = strings.ToLower(name)
+	evaluation := Evaluation{Flag: name}
+
+	if settings, ok := (*f.flags.Load())[name]; ok {
+		evaluation.Enabled, evaluation.Reason = evaluate(name, settings, Attributes(ctx))
+	} else {
+		evaluation.Reason = ReasonUnknown
+	}
+
+	attributes := []attribute.KeyValue{
+		attribute.String("feature_flag.key", evaluation.Flag),
+		attribute.Bool("feature_flag.result.value", evaluation.Enabled),
+		attribute.String("feature_flag.result.reason", evaluation.Reason),
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("feature_flag.evaluation", trace.WithAttributes(attributes...))
+	f.counter.Add(ctx, 1, metric.WithAttributes(attributes...))
+
+	return evaluation
+}
+
+func evaluate(name string, settings FlagSettings, attributes map[string]string) (bool, string) {
+	if !settings.Enabled {
+		return false, ReasonDisabled
+	}
+
+	for _, rule := range settings.Rules {
+		if value, ok := attributes[rule.Attribute]; ok && slices.Contains(rule.Values, value) {
+			return true, ReasonRule
+		}
+	}
+
+	switch settings.Rollout {
+	case 0:
+		return false, ReasonStatic
+	case 100:
+		return true, ReasonStatic
+	}
+
+	key, ok := attributes[settings.RolloutAttribute]
+	if !ok || key == "" {
+		return false, ReasonRollout
+	}
+
+	return bucket(name, key) < settings.Rollout, ReasonRollout
+}
+
+// bucket returns a stable bucket in [0, 100) for a flag and a rollout key,
+// so a key keeps its evaluation when the rollout percentage grows.
+func bucket(name string, key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + key))
+
+	return int(h.Sum32() % 100)
+}
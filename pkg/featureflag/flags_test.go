@@ -0,0 +1,97 @@
+package featureflag_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/featureflag"
+	"github.com/go-oryn/oryn-sandbox/pkg/otel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestFlags(t *testing.T) {
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"flags": map[string]any{
+			"off": map[string]any{
+				"enabled": false,
+			},
+			"on": map[string]any{
+				"enabled": true,
+			},
+			"newCheckout": map[string]any{
+				"enabled": true,
+			},
+			"targeted": map[string]any{
+				"enabled": true,
+				"rollout": 0,
+				"rules": []any{
+					map[string]any{"attribute": "tenant.id", "values": []any{"acme"}},
+				},
+			},
+			"half": map[string]any{
+				"enabled": true,
+				"rollout": 50,
+			},
+		},
+	}))
+	require.NoError(t, err)
+
+	flags, err := featureflag.NewFlags(cfg, newTelemetry())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assert.False(t, flags.Enabled(ctx, "off"))
+	assert.True(t, flags.Enabled(ctx, "on"))
+	assert.True(t, flags.Enabled(ctx, "newCheckout"))
+	assert.Equal(t, featureflag.Evaluation{Flag: "newcheckout", Enabled: true, Reason: featureflag.ReasonStatic}, flags.Evaluate(ctx, "NEWCHECKOUT"))
+	assert.Equal(t, featureflag.ReasonUnknown, flags.Evaluate(ctx, "missing").Reason)
+
+	assert.False(t, flags.Enabled(ctx, "targeted"))
+	acmeCtx, err := featureflag.ContextWithAttributes(ctx, map[string]string{"tenant.id": "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, featureflag.Evaluation{
+		Flag:    "targeted",
+		Enabled: true,
+		Reason:  featureflag.ReasonRule,
+	}, flags.Evaluate(acmeCtx, "targeted"))
+
+	// no rollout attribute
+	assert.False(t, flags.Enabled(ctx, "half"))
+
+	enabled := 0
+	for _, user := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		userCtx, err := featureflag.ContextWithAttributes(ctx, map[string]string{"user.id": user})
+		require.NoError(t, err)
+
+		// stable for a user
+		assert.Equal(t, flags.Enabled(userCtx, "half"), flags.Enabled(userCtx, "half"))
+		if flags.Enabled(userCtx, "half") {
+			enabled++
+		}
+	}
+	assert.Greater(t, enabled, 0)
+	assert.Less(t, enabled, 10)
+}
+
+func TestFlagsInvalidSettings(t *testing.T) {
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"flags.invalid.rollout": 150,
+	}))
+	require.NoError(t, err)
+
+	_, err = featureflag.NewFlags(cfg, newTelemetry())
+	require.ErrorContains(t, err, "flags.invalid.rollout: invalid value 150, failed on max validation")
+}
+
+func newTelemetry() otel.Telemetry {
+	return otel.NewTelemetryWrapper(
+		slog.New(slog.DiscardHandler),
+		metricnoop.NewMeterProvider().Meter("test"),
+		tracenoop.NewTracerProvider().Tracer("test"),
+	)
+}
@@ -0,0 +1,30 @@
+package featureflag
+
+import (
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/otel"
+	"go.uber.org/fx"
+)
+
+const (
+	ModuleName    = "featureflag"
+	ModuleSection = "flags"
+)
+
+var Module = fx.Module(
+	ModuleName,
+	config.AsCustomSchema(schema{}),
+	fx.Provide(
+		ProvideFlags,
+	),
+)
+
+type ProvideFlagsParams struct {
+	fx.In
+	Config    *config.Config
+	Telemetry otel.Telemetry
+}
+
+func ProvideFlags(params ProvideFlagsParams) (*Flags, error) {
+	return NewFlags(params.Config, params.Telemetry)
+}
@@ -0,0 +1,54 @@
+package featureflag
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+)
+
+// FlagSettings configures a flag, under flags.<name>.
+// A disabled flag is off. An enabled flag is on for the evaluation contexts matching one of its rules,
+// and for the rollout percentage of the others, bucketed by their rollout attribute.
+type FlagSettings struct {
+	Enabled          bool           `mapstructure:"enabled"`
+	Rollout          int            `mapstructure:"rollout" default:"100" validate:"min=0,max=100"`
+	RolloutAttribute string         `mapstructure:"rollout_attribute" default:"user.id" validate:"required"`
+	Rules            []RuleSettings `mapstructure:"rules" validate:"dive"`
+}
+
+// RuleSettings matches the evaluation contexts with an attribute holding one of the values.
+type RuleSettings struct {
+	Attribute string   `mapstructure:"attribute" validate:"required"`
+	Values    []string `mapstructure:"values" validate:"required,min=1"`
+}
+
+// LoadSettings binds the flags section, by flag name.
+func LoadSettings(cfg *config.Config) (map[string]FlagSettings, error) {
+	names := slices.Sorted(maps.Keys(cfg.GetStringMap(ModuleSection)))
+
+	flags := make(map[string]FlagSettings, len(names))
+	for _, name := range names {
+		settings, err := config.Bind[FlagSettings](cfg, fmt.Sprintf("%s.%s", ModuleSection, name))
+		if err != nil {
+			return nil, err
+		}
+
+		flags[name] = settings
+	}
+
+	return flags, nil
+}
+
+type schema struct{}
+
+func (schema) Section() string {
+	return ModuleSection
+}
+
+func (schema) Validate(cfg *config.Config) error {
+	_, err := LoadSettings(cfg)
+
+	return err
+}
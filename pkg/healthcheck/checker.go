@@ -2,22 +2,47 @@ package healthcheck
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
 )
 
 const (
 	Healthy   = "healthy"
+	Degraded  = "degraded"
 	Unhealthy = "unhealthy"
+	// Unknown is the status of the probes waiting for their first background probing.
+	Unknown = "unknown"
 )
 
-type Probe interface {
-	Name() string
-	Probe(ctx context.Context) error
+type ProbeResult struct {
+	Status        string        `json:"status"`
+	ComponentType string        `json:"-"`
+	Critical      bool          `json:"critical"`
+	Latency       time.Duration `json:"latency"`
+	Error         string        `json:"error,omitempty"`
+	CheckedAt     time.Time     `json:"checked_at"`
+	LastSuccess   *time.Time    `json:"last_success,omitempty"`
+	Details       any           `json:"details,omitempty"`
 }
 
-type ProbeResult struct {
-	Status string `json:"status"`
+// MarshalJSON reports the latency as a duration string, like 1.5ms.
+func (r ProbeResult) MarshalJSON() ([]byte, error) {
+	type probeResult ProbeResult
+
+	return json.Marshal(struct {
+		probeResult
+		Latency string `json:"latency"`
+	}{
+		probeResult: probeResult(r),
+		Latency:     r.Latency.String(),
+	})
 }
 
 type CheckerResult struct {
@@ -29,53 +54,178 @@ func (r CheckerResult) Healthy() bool {
 	return r.Status == Healthy
 }
 
+// Unhealthy returns true if a critical probe failed, or is waiting for its first background probing,
+// a degraded result is not unhealthy.
+func (r CheckerResult) Unhealthy() bool {
+	return r.Status == Unhealthy || r.Status == Unknown
+}
+
 type Checker struct {
-	logger *slog.Logger
-	probes []Probe
+	logger   *slog.Logger
+	metrics  *checkerMetrics
+	settings CheckerSettings
+	probes   []registeredProbe
+	unready  atomic.Bool
+	mu       sync.Mutex
+	results  map[string]ProbeResult
+	cancel   context.CancelFunc
+	done     chan struct{}
 }
 
-func NewChecker(logger *slog.Logger, probes ...Probe) *Checker {
-	return &Checker{
-		logger: logger,
-		probes: probes,
+// NewChecker returns a Checker recording the probes metrics with the meter,
+// and logging the probes status changes (a probe is assumed healthy before its first result).
+// The probes results are keyed by their names, which must be unique.
+func NewChecker(
+	logger *slog.Logger,
+	meter metric.Meter,
+	settings CheckerSettings,
+	registrations ...ProbeRegistration,
+) (*Checker, error) {
+	metrics, err := newCheckerMetrics(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	probes := make([]registeredProbe, 0, len(registrations))
+	for _, registration := range registrations {
+		name := registration.Probe.Name()
+		if slices.ContainsFunc(probes, func(p registeredProbe) bool { return p.probe.Name() == name }) {
+			return nil, fmt.Errorf("duplicate health check probe %s", name)
+		}
+
+		probes = append(probes, registeredProbe{
+			probe:   registration.Probe,
+			options: newProbeOptions(registration.Options...),
+		})
+	}
+
+	checker := &Checker{
+		logger:   logger,
+		metrics:  metrics,
+		settings: settings,
+		probes:   probes,
+		results:  make(map[string]ProbeResult, len(probes)),
 	}
+
+	_, err = meter.RegisterCallback(checker.observe, metrics.status)
+	if err != nil {
+		return nil, err
+	}
+
+	return checker, nil
 }
 
-func (c *Checker) Check(ctx context.Context) CheckerResult {
-	result := CheckerResult{
-		Status:       Healthy,
-		ProbeResults: make(map[string]ProbeResult, len(c.probes)),
+// SetReady overrides the readiness, a checker not ready fails its readiness checks, whatever its probes results.
+func (c *Checker) SetReady(ready bool) {
+	c.unready.Store(!ready)
+}
+
+// Ready returns false if the readiness was overridden as not ready.
+func (c *Checker) Ready() bool {
+	return !c.unready.Load()
+}
+
+// Start probes in background, at once then on the checker interval, the checks then return the cached results,
+// or an unknown status for the probes waiting for their first result. It does nothing if no interval is configured.
+func (c *Checker) Start() {
+	c.mu.Lock()
+	if c.settings.Interval <= 0 || c.cancel != nil {
+		c.mu.Unlock()
+
+		return
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(c.probes))
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go func() {
+		defer close(c.done)
 
-	var mut sync.Mutex
+		c.run(ctx, c.probes)
 
-	for _, probe := range c.probes {
-		go func(ctx context.Context, p Probe) {
-			defer wg.Done()
+		ticker := time.NewTicker(c.settings.Interval)
+		defer ticker.Stop()
 
-			probeResult := ProbeResult{
-				Status: Healthy,
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.run(ctx, c.probes)
 			}
+		}
+	}()
+}
 
-			err := p.Probe(ctx)
+// Stop stops the background probing.
+func (c *Checker) Stop() {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel = nil
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
 
-			mut.Lock()
-			if err != nil {
-				c.logger.ErrorContext(ctx, "health check probe failed", "probe", p.Name(), "error", err)
+// Check runs the probes of the kinds, or all the probes if no kind is given.
+// When probing in background, the cached probes results are returned instead, with an unknown status until the first
+// background probing finishes: it makes the check unhealthy, or degraded for a non critical probe.
+func (c *Checker) Check(ctx context.Context, kinds ...Kind) CheckerResult {
+	var probes []registeredProbe
+	for _, p := range c.probes {
+		if p.matches(kinds) {
+			probes = append(probes, p)
+		}
+	}
 
-				result.Status = Unhealthy
-				probeResult.Status = Unhealthy
+	results, background := c.cached(probes)
+	if len(results) < len(probes) {
+		var missing []registeredProbe
+		for _, p := range probes {
+			if _, ok := results[p.probe.Name()]; !ok {
+				missing = append(missing, p)
 			}
+		}
 
-			result.ProbeResults[p.Name()] = probeResult
-			mut.Unlock()
-		}(ctx, probe)
+		if background {
+			for _, p := range missing {
+				results[p.probe.Name()] = ProbeResult{
+					Status:   Unknown,
+					Critical: !p.options.nonCritical,
+					Error:    "waiting for the first background probing",
+				}
+			}
+		} else {
+			for name, result := range c.run(ctx, missing) {
+				results[name] = result
+			}
+		}
 	}
 
-	wg.Wait()
+	result := CheckerResult{
+		Status:       Healthy,
+		ProbeResults: results,
+	}
+
+	for _, probeResult := range results {
+		switch {
+		case probeResult.Status == Unhealthy:
+			result.Status = Unhealthy
+		case probeResult.Status == Unknown && probeResult.Critical && result.Status != Unhealthy:
+			result.Status = Unknown
+		case (probeResult.Status == Degraded || probeResult.Status == Unknown) && result.Status == Healthy:
+			result.Status = Degraded
+		}
+	}
+
+	if !c.Ready() && (len(kinds) == 0 || slices.Contains(kinds, Readiness)) {
+		result.Status = Unhealthy
+	}
 
 	if result.Healthy() {
 		c.logger.DebugContext(ctx, "health check success")
@@ -83,3 +233,119 @@ func (c *Checker) Check(ctx context.Context) CheckerResult {
 
 	return result
 }
+
+// cached returns the probes cached results, and true if probing in background.
+func (c *Checker) cached(probes []registeredProbe) (map[string]ProbeResult, bool) {
+	results := make(map[string]ProbeResult, len(probes))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel == nil {
+		return results, false
+	}
+
+	for _, p := range probes {
+		if result, ok := c.results[p.probe.Name()]; ok {
+			results[p.probe.Name()] = result
+		}
+	}
+
+	return results, true
+}
+
+// run runs the probes concurrently, and stores their results.
+func (c *Checker) run(ctx context.Context, probes []registeredProbe) map[string]ProbeResult {
+	results := make(map[string]ProbeResult, len(probes))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for _, p := range probes {
+		wg.Go(func() {
+			result := c.probe(ctx, p)
+
+			mu.Lock()
+			results[p.probe.Name()] = result
+			mu.Unlock()
+		})
+	}
+
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, result := range results {
+		previous, ok := c.results[name]
+		if !ok {
+			previous.Status = Healthy
+		}
+
+		if result.LastSuccess == nil {
+			result.LastSuccess = previous.LastSuccess
+		}
+
+		c.record(ctx, name, previous.Status, result)
+
+		c.results[name] = result
+		results[name] = result
+	}
+
+	return results
+}
+
+func (c *Checker) probe(ctx context.Context, p registeredProbe) ProbeResult {
+	timeout := p.options.timeout
+	if timeout <= 0 {
+		timeout = c.settings.Timeout
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// a probe ignoring its context cannot block the check beyond its timeout
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.probe.Probe(probeCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-probeCtx.Done():
+		err = fmt.Errorf("probe timed out after %s: %w", timeout, probeCtx.Err())
+	}
+
+	componentType := DefaultComponentType
+	if componentProbe, ok := p.probe.(ComponentProbe); ok {
+		componentType = componentProbe.ComponentType()
+	}
+
+	result := ProbeResult{
+		Status:        Healthy,
+		ComponentType: componentType,
+		Critical:      !p.options.nonCritical,
+		Latency:       time.Since(start),
+		CheckedAt:     start,
+	}
+
+	if err != nil {
+		result.Status = Unhealthy
+		if p.options.nonCritical {
+			result.Status = Degraded
+		}
+
+		result.Error = err.Error()
+	} else {
+		result.LastSuccess = &start
+	}
+
+	if detailedProbe, ok := p.probe.(DetailedProbe); ok {
+		result.Details = detailedProbe.Details()
+	}
+
+	return result
+}
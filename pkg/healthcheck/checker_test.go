@@ -0,0 +1,256 @@
+package healthcheck_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type testProbe struct {
+	name  string
+	err   error
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (p *testProbe) Name() string {
+	return p.name
+}
+
+func (p *testProbe) Probe(ctx context.Context) error {
+	p.calls.Add(1)
+
+	select {
+	case <-time.After(p.delay):
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type detailedProbe struct {
+	testProbe
+}
+
+func (p *detailedProbe) Details() any {
+	return map[string]int32{"calls": p.calls.Load()}
+}
+
+func TestCheckerDetails(t *testing.T) {
+	checker := newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{Probe: &detailedProbe{testProbe{name: "workers"}}},
+	)
+
+	res := checker.Check(context.Background())
+
+	assert.Equal(t, map[string]int32{"calls": 1}, res.ProbeResults["workers"].Details)
+}
+
+func TestCheckerDuplicateProbes(t *testing.T) {
+	_, err := healthcheck.NewChecker(
+		slog.New(slog.DiscardHandler),
+		noop.NewMeterProvider().Meter("test"),
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{Probe: &testProbe{name: "db"}},
+		healthcheck.ProbeRegistration{Probe: &testProbe{name: "db"}},
+	)
+	assert.EqualError(t, err, "duplicate health check probe db")
+}
+
+func TestCheckerKinds(t *testing.T) {
+	checker := newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{
+			Probe:   &testProbe{name: "db", err: errors.New("db error")},
+			Options: []healthcheck.ProbeOption{healthcheck.Readiness, healthcheck.Startup},
+		},
+		healthcheck.ProbeRegistration{
+			Probe:   &testProbe{name: "workers"},
+			Options: []healthcheck.ProbeOption{healthcheck.Liveness},
+		},
+		healthcheck.ProbeRegistration{
+			Probe: &testProbe{name: "default"},
+		},
+	)
+
+	ctx := context.Background()
+
+	liveness := checker.Check(ctx, healthcheck.Liveness)
+	assert.True(t, liveness.Healthy())
+	assert.Len(t, liveness.ProbeResults, 1)
+
+	readiness := checker.Check(ctx, healthcheck.Readiness)
+	assert.False(t, readiness.Healthy())
+	assert.Equal(t, []string{"db", "default"}, slices.Sorted(maps.Keys(readiness.ProbeResults)))
+
+	all := checker.Check(ctx)
+	assert.False(t, all.Healthy())
+	assert.Len(t, all.ProbeResults, 3)
+
+	// readiness override
+	checker = newChecker(t, healthcheck.CheckerSettings{Timeout: time.Second}, healthcheck.ProbeRegistration{
+		Probe: &testProbe{name: "ok"},
+	})
+	checker.SetReady(false)
+
+	assert.False(t, checker.Check(ctx, healthcheck.Readiness).Healthy())
+	assert.True(t, checker.Check(ctx, healthcheck.Liveness).Healthy())
+}
+
+func TestCheckerStatuses(t *testing.T) {
+	checker := newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{
+			Probe: &testProbe{name: "ok"},
+		},
+		healthcheck.ProbeRegistration{
+			Probe:   &testProbe{name: "cache", err: errors.New("cache error")},
+			Options: []healthcheck.ProbeOption{healthcheck.NonCritical()},
+		},
+	)
+
+	result := checker.Check(context.Background())
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+	assert.False(t, result.Unhealthy())
+	assert.Equal(t, healthcheck.Degraded, result.ProbeResults["cache"].Status)
+	assert.Equal(t, "cache error", result.ProbeResults["cache"].Error)
+	assert.Nil(t, result.ProbeResults["cache"].LastSuccess)
+	assert.NotNil(t, result.ProbeResults["ok"].LastSuccess)
+
+	checker = newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{
+			Probe:   &testProbe{name: "slow", delay: time.Second},
+			Options: []healthcheck.ProbeOption{healthcheck.WithTimeout(10 * time.Millisecond)},
+		},
+	)
+
+	result = checker.Check(context.Background())
+	assert.True(t, result.Unhealthy())
+	assert.Contains(t, result.ProbeResults["slow"].Error, "probe timed out after 10ms")
+}
+
+func TestCheckerInterval(t *testing.T) {
+	probe := &testProbe{name: "db"}
+
+	checker := newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second, Interval: time.Hour},
+		healthcheck.ProbeRegistration{Probe: probe},
+	)
+
+	checker.Start()
+	defer checker.Stop()
+
+	assert.Eventually(t, func() bool {
+		return checker.Check(context.Background()).Healthy()
+	}, time.Second, time.Millisecond)
+
+	for range 10 {
+		assert.True(t, checker.Check(context.Background()).Healthy())
+	}
+
+	assert.Equal(t, int32(1), probe.calls.Load())
+}
+
+func TestCheckerIntervalStarting(t *testing.T) {
+	checker := newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second, Interval: time.Hour},
+		healthcheck.ProbeRegistration{Probe: &testProbe{name: "db", delay: 100 * time.Millisecond}},
+		healthcheck.ProbeRegistration{
+			Probe:   &testProbe{name: "cache", delay: 100 * time.Millisecond},
+			Options: []healthcheck.ProbeOption{healthcheck.Liveness, healthcheck.NonCritical()},
+		},
+	)
+
+	start := time.Now()
+	checker.Start()
+	defer checker.Stop()
+
+	// the first background probing does not block the start, nor the checks
+	result := checker.Check(context.Background())
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, healthcheck.Unknown, result.Status)
+	assert.True(t, result.Unhealthy())
+	assert.Equal(t, healthcheck.Unknown, result.ProbeResults["db"].Status)
+	assert.Equal(t, healthcheck.Unknown, result.ProbeResults["cache"].Status)
+
+	// a non critical probe waiting for its first result only degrades the check
+	result = checker.Check(context.Background(), healthcheck.Liveness)
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+	assert.False(t, result.Unhealthy())
+
+	assert.Eventually(t, func() bool {
+		return checker.Check(context.Background()).Healthy()
+	}, time.Second, time.Millisecond)
+}
+
+func TestCheckerMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	probe := &testProbe{name: "db"}
+
+	checker, err := healthcheck.NewChecker(
+		slog.New(slog.DiscardHandler),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{Probe: probe},
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	checker.Check(ctx)
+	probe.err = errors.New("db error")
+	checker.Check(ctx)
+	checker.Check(ctx)
+	probe.err = nil
+	checker.Check(ctx)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	transitions := metrics["healthcheck.probe.transitions"].(metricdata.Sum[int64])
+	assert.Len(t, transitions.DataPoints, 2)
+
+	status := metrics["healthcheck.probe.status"].(metricdata.Gauge[float64])
+	assert.Equal(t, 1.0, status.DataPoints[0].Value)
+
+	duration := metrics["healthcheck.probe.duration"].(metricdata.Histogram[float64])
+	var count uint64
+	for _, dp := range duration.DataPoints {
+		count += dp.Count
+	}
+	assert.Equal(t, uint64(4), count)
+}
+
+func newChecker(tb testing.TB, settings healthcheck.CheckerSettings, registrations ...healthcheck.ProbeRegistration) *healthcheck.Checker {
+	tb.Helper()
+
+	checker, err := healthcheck.NewChecker(slog.New(slog.DiscardHandler), noop.NewMeterProvider().Meter("test"), settings, registrations...)
+	require.NoError(tb, err)
+
+	return checker
+}
@@ -0,0 +1,152 @@
+package healthcheck
+
+import (
+	"fmt"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MIMEApplicationHealthJSON = "application/health+json"
+	MIMETextPlainPrometheus   = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultComponentType is the health+json component type of the probes not implementing ComponentProbe.
+const DefaultComponentType = "component"
+
+// ComponentProbe is a Probe reporting the type of its component in the health+json format, like datastore.
+type ComponentProbe interface {
+	Probe
+	ComponentType() string
+}
+
+// HealthResponse is the application/health+json draft format response,
+// see https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check.
+type HealthResponse struct {
+	Status  string                   `json:"status"`
+	Version string                   `json:"version,omitempty"`
+	Checks  map[string][]HealthCheck `json:"checks"`
+}
+
+type HealthCheck struct {
+	ComponentType string  `json:"componentType"`
+	ObservedValue float64 `json:"observedValue"`
+	ObservedUnit  string  `json:"observedUnit"`
+	Status        string  `json:"status"`
+	Time          string  `json:"time"`
+	Output        string  `json:"output,omitempty"`
+}
+
+func healthStatus(status string) string {
+	switch status {
+	case Healthy:
+		return "pass"
+	case Degraded:
+		return "warn"
+	default:
+		return "fail"
+	}
+}
+
+// NewHealthResponse converts a result into the health+json format, with the probes errors as output if verbose.
+func NewHealthResponse(result CheckerResult, version string, verbose bool) HealthResponse {
+	response := HealthResponse{
+		Status:  healthStatus(result.Status),
+		Version: version,
+		Checks:  make(map[string][]HealthCheck, len(result.ProbeResults)),
+	}
+
+	for name, probeResult := range result.ProbeResults {
+		check := HealthCheck{
+			ComponentType: probeResult.ComponentType,
+			ObservedValue: float64(probeResult.Latency.Microseconds()) / 1000,
+			ObservedUnit:  "ms",
+			Status:        healthStatus(probeResult.Status),
+			Time:          probeResult.CheckedAt.UTC().Format(time.RFC3339),
+		}
+
+		if verbose {
+			check.Output = probeResult.Error
+		}
+
+		response.Checks[name+":responseTime"] = []HealthCheck{check}
+	}
+
+	return response
+}
+
+// PrometheusText converts a result into the Prometheus text exposition format.
+func PrometheusText(result CheckerResult) string {
+	var b strings.Builder
+
+	names := make([]string, 0, len(result.ProbeResults))
+	for name := range result.ProbeResults {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	b.WriteString("# HELP healthcheck_status Status of the health check: 1 if healthy, 0.5 if degraded, 0 if unhealthy.\n")
+	b.WriteString("# TYPE healthcheck_status gauge\n")
+	fmt.Fprintf(&b, "healthcheck_status %s\n", formatFloat(statusValue(result.Status)))
+
+	b.WriteString("# HELP healthcheck_probe_status Status of the health check probes: 1 if healthy, 0.5 if degraded, 0 if unhealthy.\n")
+	b.WriteString("# TYPE healthcheck_probe_status gauge\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "healthcheck_probe_status{probe=\"%s\"} %s\n", escapeLabelValue(name), formatFloat(statusValue(result.ProbeResults[name].Status)))
+	}
+
+	b.WriteString("# HELP healthcheck_probe_duration_seconds Duration of the health check probes.\n")
+	b.WriteString("# TYPE healthcheck_probe_duration_seconds gauge\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "healthcheck_probe_duration_seconds{probe=\"%s\"} %s\n", escapeLabelValue(name), formatFloat(result.ProbeResults[name].Latency.Seconds()))
+	}
+
+	return b.String()
+}
+
+// labelValueEscaper escapes the label values as the text exposition format expects: only backslashes, double quotes and
+// line feeds, unlike Go quoting.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// negotiate returns the supported media type with the highest quality in the Accept header,
+// or an empty string to use the default format.
+func negotiate(accept string) string {
+	best, bestQuality := "", 0.0
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case MIMEApplicationHealthJSON, "text/plain":
+		default:
+			continue
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = mediaType, quality
+		}
+	}
+
+	return best
+}
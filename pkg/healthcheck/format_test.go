@@ -0,0 +1,20 @@
+package healthcheck_test
+
+import (
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusTextLabels(t *testing.T) {
+	text := healthcheck.PrometheusText(healthcheck.CheckerResult{
+		Status: healthcheck.Healthy,
+		ProbeResults: map[string]healthcheck.ProbeResult{
+			"api \"eu\"\\1\nété\t": {Status: healthcheck.Healthy},
+		},
+	})
+
+	// only backslashes, double quotes and line feeds are escaped, the other characters are kept as is
+	assert.Contains(t, text, `healthcheck_probe_status{probe="api \"eu\"\\1\nété`+"\t"+`"} 1`)
+}
@@ -0,0 +1,111 @@
+package healthcheck
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	probeKey      = attribute.Key("healthcheck.probe")
+	statusKey     = attribute.Key("healthcheck.status")
+	fromStatusKey = attribute.Key("healthcheck.status.from")
+)
+
+type checkerMetrics struct {
+	duration    metric.Float64Histogram
+	transitions metric.Int64Counter
+	status      metric.Float64ObservableGauge
+}
+
+func newCheckerMetrics(meter metric.Meter) (*checkerMetrics, error) {
+	duration, err := meter.Float64Histogram(
+		"healthcheck.probe.duration",
+		metric.WithDescription("Duration of the health check probes."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := meter.Int64Counter(
+		"healthcheck.probe.transitions",
+		metric.WithDescription("Number of health check probes status changes."),
+		metric.WithUnit("{transition}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := meter.Float64ObservableGauge(
+		"healthcheck.probe.status",
+		metric.WithDescription("Status of the health check probes: 1 if healthy, 0.5 if degraded, 0 if unhealthy."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkerMetrics{
+		duration:    duration,
+		transitions: transitions,
+		status:      status,
+	}, nil
+}
+
+func statusValue(status string) float64 {
+	switch status {
+	case Healthy:
+		return 1
+	case Degraded:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// observe reports the status of the probes last results.
+func (c *Checker) observe(_ context.Context, observer metric.Observer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, result := range c.results {
+		observer.ObserveFloat64(
+			c.metrics.status,
+			statusValue(result.Status),
+			metric.WithAttributes(probeKey.String(name)),
+		)
+	}
+
+	return nil
+}
+
+// record records a probe result, and logs its status change from the previous one.
+func (c *Checker) record(ctx context.Context, name string, previous string, result ProbeResult) {
+	c.metrics.duration.Record(
+		ctx,
+		result.Latency.Seconds(),
+		metric.WithAttributes(probeKey.String(name), statusKey.String(result.Status)),
+	)
+
+	if previous == result.Status {
+		return
+	}
+
+	c.metrics.transitions.Add(
+		ctx,
+		1,
+		metric.WithAttributes(probeKey.String(name), fromStatusKey.String(previous), statusKey.String(result.Status)),
+	)
+
+	attrs := []any{"probe", name, "from", previous, "to", result.Status}
+	if result.Error != "" {
+		attrs = append(attrs, "error", result.Error)
+	}
+
+	if result.Status == Healthy {
+		c.logger.InfoContext(ctx, "health check probe status changed", attrs...)
+	} else {
+		c.logger.WarnContext(ctx, "health check probe status changed", attrs...)
+	}
+}
@@ -2,9 +2,13 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/fx"
 )
 
@@ -12,6 +16,7 @@ const ModuleName = "healthcheck"
 
 var Module = fx.Module(
 	ModuleName,
+	config.AsSchema[Settings](ModuleName),
 	fx.Provide(
 		ProvideChecker,
 		ProvideServer,
@@ -20,12 +25,53 @@ var Module = fx.Module(
 
 type ProvideCheckerParams struct {
 	fx.In
-	Logger *slog.Logger
-	Probes []Probe `group:"healthcheck-probes"`
+	Lifecycle     fx.Lifecycle
+	Manager       *lifecycle.Manager
+	Config        *config.Config
+	Logger        *slog.Logger
+	MeterProvider metric.MeterProvider
+	Registrations []ProbeRegistration `group:"healthcheck-probes-registrations"`
 }
 
-func ProvideChecker(params ProvideCheckerParams) *Checker {
-	return NewChecker(params.Logger, params.Probes...)
+func ProvideChecker(params ProvideCheckerParams) (*Checker, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := NewChecker(
+		params.Logger,
+		params.MeterProvider.Meter("github.com/go-oryn/oryn-sandbox/pkg/healthcheck"),
+		settings.Checker,
+		params.Registrations...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			checker.Start()
+
+			return nil
+		},
+	})
+
+	// stop receiving traffic first on shutdown
+	params.Manager.OnStop(lifecycle.PhaseUnready, "healthcheck", func(context.Context) error {
+		checker.SetReady(false)
+
+		return nil
+	})
+
+	// keep probing while the workers stop, but not once the probed resources like the db connections are closed
+	params.Manager.OnStop(lifecycle.PhaseTelemetry, "healthcheck background probing", func(context.Context) error {
+		checker.Stop()
+
+		return nil
+	})
+
+	return checker, nil
 }
 
 type ProvideServerParams struct {
@@ -37,25 +83,54 @@ type ProvideServerParams struct {
 }
 
 func ProvideServer(params ProvideServerParams) (*Server, error) {
-	return NewServer(params.Config, params.Checker), nil
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServer(params.Config, settings, params.Checker), nil
 }
 
+// RunServer runs the health checks own HTTP server, unless they are mounted on the main servers without debug endpoints.
 func RunServer() fx.Option {
+	return runServer(false)
+}
+
+// RunDedicatedServer runs the health checks own HTTP server even if they are mounted on the main servers,
+// for the commands without main servers.
+func RunDedicatedServer() fx.Option {
+	return runServer(true)
+}
+
+func runServer(dedicated bool) fx.Option {
 	return fx.Invoke(
 		func(
-			lifecycle fx.Lifecycle,
+			lc fx.Lifecycle,
+			manager *lifecycle.Manager,
 			shutdown fx.Shutdowner,
-			config *config.Config,
+			cfg *config.Config,
 			logger *slog.Logger,
 			server *Server,
-		) {
-			address := config.GetStringOrDefault("healthcheck.httpserver.address", ":8080")
+		) error {
+			settings, err := config.Bind[Settings](cfg, ModuleName)
+			if err != nil {
+				return err
+			}
+
+			// served by the main servers instead, the debug endpoints are only served by the health checks own server
+			if server.Mounted() && !dedicated && !settings.Debug.Config.Enabled {
+				logger.Debug("mounted healthcheck routes on main servers")
+
+				return nil
+			}
+
+			address := settings.HTTPServer.Address
 
-			lifecycle.Append(fx.Hook{
+			lc.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
 					go func() {
 						err := server.HTTPServer().Start(address)
-						if err != nil {
+						if err != nil && !errors.Is(err, http.ErrServerClosed) {
 
 							logger.ErrorContext(ctx, "failed to start healthcheck HTTP server", "error", err, "address", address)
 
@@ -67,17 +142,20 @@ func RunServer() fx.Option {
 
 					return nil
 				},
-				OnStop: func(ctx context.Context) error {
-					err := server.HTTPServer().Shutdown(ctx)
-					if err != nil {
-						logger.ErrorContext(ctx, "failed to stop healthcheck HTTP server", "error", err)
+			})
 
-						return err
-					}
+			manager.OnStop(lifecycle.PhaseDrain, "healthcheck HTTP server", func(ctx context.Context) error {
+				err := server.HTTPServer().Shutdown(ctx)
+				if err != nil {
+					logger.ErrorContext(ctx, "failed to stop healthcheck HTTP server", "error", err)
 
-					return nil
-				},
+					return err
+				}
+
+				return nil
 			})
+
+			return nil
 		},
 	)
 }
@@ -0,0 +1,103 @@
+package healthcheck
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+type Probe interface {
+	Name() string
+	Probe(ctx context.Context) error
+}
+
+// DetailedProbe is a Probe reporting details in the verbose results, collected after each probing.
+type DetailedProbe interface {
+	Probe
+	Details() any
+}
+
+// Kind is the kind of check a probe belongs to.
+type Kind string
+
+const (
+	Liveness  Kind = "liveness"
+	Readiness Kind = "readiness"
+	Startup   Kind = "startup"
+)
+
+func (k Kind) apply(o *probeOptions) {
+	if !slices.Contains(o.kinds, k) {
+		o.kinds = append(o.kinds, k)
+	}
+}
+
+// ProbeOption configures a registered probe, a Kind is a ProbeOption.
+type ProbeOption interface {
+	apply(o *probeOptions)
+}
+
+type probeOptionFunc func(o *probeOptions)
+
+func (f probeOptionFunc) apply(o *probeOptions) {
+	f(o)
+}
+
+// WithTimeout overrides the checker probes timeout for the probe.
+func WithTimeout(timeout time.Duration) ProbeOption {
+	return probeOptionFunc(func(o *probeOptions) {
+		o.timeout = timeout
+	})
+}
+
+// NonCritical makes the probe failures degrade the checks status, instead of making them unhealthy.
+func NonCritical() ProbeOption {
+	return probeOptionFunc(func(o *probeOptions) {
+		o.nonCritical = true
+	})
+}
+
+type probeOptions struct {
+	kinds       []Kind
+	timeout     time.Duration
+	nonCritical bool
+}
+
+// newProbeOptions applies the options, a probe without kind belongs to the readiness checks.
+func newProbeOptions(options ...ProbeOption) probeOptions {
+	o := probeOptions{}
+	for _, opt := range options {
+		opt.apply(&o)
+	}
+
+	if len(o.kinds) == 0 {
+		o.kinds = []Kind{Readiness}
+	}
+
+	return o
+}
+
+// ProbeRegistration is a probe registered in the Checker, with its options.
+type ProbeRegistration struct {
+	Probe   Probe
+	Options []ProbeOption
+}
+
+type registeredProbe struct {
+	probe   Probe
+	options probeOptions
+}
+
+func (p registeredProbe) matches(kinds []Kind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+
+	for _, kind := range kinds {
+		if slices.Contains(p.options.kinds, kind) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+)
+
+// DiskSpaceProbe expects a file system to have a minimum free space, in bytes and in percent.
+type DiskSpaceProbe struct {
+	name           string
+	path           string
+	minFreeBytes   uint64
+	minFreePercent float64
+}
+
+func NewDiskSpaceProbe(name string, path string, minFreeBytes uint64, minFreePercent float64) *DiskSpaceProbe {
+	return &DiskSpaceProbe{
+		name:           name,
+		path:           path,
+		minFreeBytes:   minFreeBytes,
+		minFreePercent: minFreePercent,
+	}
+}
+
+func (p *DiskSpaceProbe) Name() string {
+	return p.name
+}
+
+func (p *DiskSpaceProbe) ComponentType() string {
+	return "system"
+}
+
+func (p *DiskSpaceProbe) Probe(context.Context) error {
+	free, total, err := diskSpace(p.path)
+	if err != nil {
+		return fmt.Errorf("cannot read %s disk space: %w", p.path, err)
+	}
+
+	if free < p.minFreeBytes {
+		return fmt.Errorf("%s free space %d bytes is below %d bytes", p.path, free, p.minFreeBytes)
+	}
+
+	if total > 0 {
+		if percent := float64(free) / float64(total) * 100; percent < p.minFreePercent {
+			return fmt.Errorf("%s free space %.2f%% is below %.2f%%", p.path, percent, p.minFreePercent)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package probes
+
+import "errors"
+
+func diskSpace(string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk space probe is not supported on this platform")
+}
@@ -0,0 +1,15 @@
+//go:build linux || darwin
+
+package probes
+
+import "syscall"
+
+func diskSpace(path string) (free uint64, total uint64, err error) {
+	var stat syscall.Statfs_t
+	if err = syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	// the block size type depends on the platform
+	return stat.Bavail * uint64(stat.Bsize), stat.Blocks * uint64(stat.Bsize), nil
+}
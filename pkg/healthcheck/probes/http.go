@@ -0,0 +1,57 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPProbe requests an upstream, and expects a status code.
+type HTTPProbe struct {
+	name           string
+	client         *http.Client
+	method         string
+	url            string
+	expectedStatus int
+}
+
+func NewHTTPProbe(name string, client *http.Client, method string, url string, expectedStatus int) *HTTPProbe {
+	return &HTTPProbe{
+		name:           name,
+		client:         client,
+		method:         method,
+		url:            url,
+		expectedStatus: expectedStatus,
+	}
+}
+
+func (p *HTTPProbe) Name() string {
+	return p.name
+}
+
+func (p *HTTPProbe) ComponentType() string {
+	return "http"
+}
+
+func (p *HTTPProbe) Probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode != p.expectedStatus {
+		return fmt.Errorf("unexpected status %d from %s, expected %d", res.StatusCode, p.url, p.expectedStatus)
+	}
+
+	return nil
+}
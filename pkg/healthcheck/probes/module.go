@@ -0,0 +1,95 @@
+package probes
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"go.uber.org/fx"
+)
+
+const ModuleName = "healthcheck-probes"
+
+// Module registers the probes configured under healthcheck.probes, see healthcheck.ProbeSettings.
+var Module = fx.Module(
+	ModuleName,
+	healthcheck.AsProbeRegistrations(ProvideProbes),
+)
+
+type ProvideProbesParams struct {
+	fx.In
+	Config *config.Config
+	Client *http.Client `optional:"true"`
+}
+
+func ProvideProbes(params ProvideProbesParams) ([]healthcheck.ProbeRegistration, error) {
+	settings, err := config.Bind[healthcheck.Settings](params.Config, healthcheck.ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	var registrations []healthcheck.ProbeRegistration
+
+	for _, name := range slices.Sorted(maps.Keys(settings.Probes)) {
+		probeSettings := settings.Probes[name]
+
+		// registered by the db module, which owns the db
+		if probeSettings.Type == "db" {
+			continue
+		}
+
+		probe, err := NewProbe(name, probeSettings, params.Client)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create healthcheck.probes.%s: %w", name, err)
+		}
+
+		registrations = append(registrations, healthcheck.ProbeRegistration{
+			Probe:   probe,
+			Options: probeSettings.Options(),
+		})
+	}
+
+	return registrations, nil
+}
+
+// NewProbe creates a probe from its settings, the client is required for the http probes.
+// The db probes are created by the db module instead, see db.ProvideConfiguredProbes.
+func NewProbe(name string, settings healthcheck.ProbeSettings, client *http.Client) (healthcheck.Probe, error) {
+	switch settings.Type {
+	case "http":
+		if client == nil {
+			return nil, errors.New("http probe requires an http client")
+		}
+
+		return NewHTTPProbe(
+			name,
+			client,
+			valueOrDefault(settings.Method, http.MethodGet),
+			settings.URL,
+			valueOrDefault(settings.ExpectedStatus, http.StatusOK),
+		), nil
+	case "tcp":
+		return NewTCPProbe(name, settings.Address), nil
+	case "disk":
+		return NewDiskSpaceProbe(name, valueOrDefault(settings.Path, "/"), settings.MinFreeBytes, settings.MinFreePercent), nil
+	case "goroutines":
+		return NewGoroutinesProbe(name, settings.MaxGoroutines), nil
+	case "heap":
+		return NewHeapProbe(name, settings.MaxHeapBytes), nil
+	default:
+		return nil, fmt.Errorf("unknown probe type %s", settings.Type)
+	}
+}
+
+func valueOrDefault[T comparable](value T, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+
+	return value
+}
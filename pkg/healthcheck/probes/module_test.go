@@ -0,0 +1,113 @@
+package probes_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck/probes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNewProbe(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	tests := []struct {
+		name     string
+		settings healthcheck.ProbeSettings
+		err      string
+	}{
+		{
+			name:     "http",
+			settings: healthcheck.ProbeSettings{Type: "http", URL: upstream.URL, ExpectedStatus: http.StatusNoContent},
+		},
+		{
+			name:     "http unexpected status",
+			settings: healthcheck.ProbeSettings{Type: "http", URL: upstream.URL},
+			err:      "unexpected status 204",
+		},
+		{
+			name:     "tcp",
+			settings: healthcheck.ProbeSettings{Type: "tcp", Address: upstream.Listener.Addr().String()},
+		},
+		{
+			name:     "tcp closed",
+			settings: healthcheck.ProbeSettings{Type: "tcp", Address: address},
+			err:      "connection refused",
+		},
+		{
+			name:     "disk",
+			settings: healthcheck.ProbeSettings{Type: "disk", MinFreeBytes: 1},
+		},
+		{
+			name:     "goroutines",
+			settings: healthcheck.ProbeSettings{Type: "goroutines", MaxGoroutines: 1},
+			err:      "goroutines is above 1",
+		},
+		{
+			name:     "heap",
+			settings: healthcheck.ProbeSettings{Type: "heap", MaxHeapBytes: 1 << 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe, err := probes.NewProbe(tt.name, tt.settings, upstream.Client())
+			require.NoError(t, err)
+
+			err = probe.Probe(context.Background())
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+
+	_, err = probes.NewProbe("db", healthcheck.ProbeSettings{Type: "db"}, nil)
+	assert.EqualError(t, err, "unknown probe type db")
+}
+
+func TestModuleWithoutDB(t *testing.T) {
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"healthcheck.probes.db.type":                   "db",
+		"healthcheck.probes.goroutines.type":           "goroutines",
+		"healthcheck.probes.goroutines.max_goroutines": 100,
+	}))
+	require.NoError(t, err)
+
+	var registrations []healthcheck.ProbeRegistration
+
+	// the db probes are registered by the db module, the probes module does not require a db
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fx.Supply(cfg),
+		probes.Module,
+		fx.Invoke(fx.Annotate(
+			func(r []healthcheck.ProbeRegistration) {
+				registrations = r
+			},
+			fx.ParamTags(`group:"healthcheck-probes-registrations"`),
+		)),
+	)
+	app.RequireStart().RequireStop()
+
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "goroutines", registrations[0].Probe.Name())
+}
@@ -0,0 +1,76 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+)
+
+// GoroutinesProbe expects a maximum number of goroutines.
+type GoroutinesProbe struct {
+	name          string
+	maxGoroutines int
+}
+
+func NewGoroutinesProbe(name string, maxGoroutines int) *GoroutinesProbe {
+	return &GoroutinesProbe{
+		name:          name,
+		maxGoroutines: maxGoroutines,
+	}
+}
+
+func (p *GoroutinesProbe) Name() string {
+	return p.name
+}
+
+func (p *GoroutinesProbe) ComponentType() string {
+	return "system"
+}
+
+func (p *GoroutinesProbe) Probe(context.Context) error {
+	if count := runtime.NumGoroutine(); count > p.maxGoroutines {
+		return fmt.Errorf("%d goroutines is above %d", count, p.maxGoroutines)
+	}
+
+	return nil
+}
+
+// heapMetric is read from runtime/metrics, which does not stop the world like runtime.ReadMemStats.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// HeapProbe expects a maximum heap size, in bytes of live and not yet swept objects.
+type HeapProbe struct {
+	name     string
+	maxBytes uint64
+}
+
+func NewHeapProbe(name string, maxBytes uint64) *HeapProbe {
+	return &HeapProbe{
+		name:     name,
+		maxBytes: maxBytes,
+	}
+}
+
+func (p *HeapProbe) Name() string {
+	return p.name
+}
+
+func (p *HeapProbe) ComponentType() string {
+	return "system"
+}
+
+func (p *HeapProbe) Probe(context.Context) error {
+	samples := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(samples)
+
+	if samples[0].Value.Kind() != metrics.KindUint64 {
+		return fmt.Errorf("unsupported runtime metric %s", heapMetric)
+	}
+
+	if heap := samples[0].Value.Uint64(); heap > p.maxBytes {
+		return fmt.Errorf("%d heap bytes is above %d", heap, p.maxBytes)
+	}
+
+	return nil
+}
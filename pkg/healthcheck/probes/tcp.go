@@ -0,0 +1,37 @@
+package probes
+
+import (
+	"context"
+	"net"
+)
+
+// TCPProbe dials an address.
+type TCPProbe struct {
+	name    string
+	address string
+	dialer  net.Dialer
+}
+
+func NewTCPProbe(name string, address string) *TCPProbe {
+	return &TCPProbe{
+		name:    name,
+		address: address,
+	}
+}
+
+func (p *TCPProbe) Name() string {
+	return p.name
+}
+
+func (p *TCPProbe) ComponentType() string {
+	return "system"
+}
+
+func (p *TCPProbe) Probe(ctx context.Context) error {
+	conn, err := p.dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -1,15 +1,46 @@
 package healthcheck
 
 import (
+	"fmt"
+	"sync/atomic"
+
 	"go.uber.org/fx"
 )
 
-func AsProbe(constructor any) fx.Option {
+// probesCount numbers the probes registered with AsProbe, to provide each of them under its own name.
+var probesCount atomic.Int64
+
+// AsProbeRegistrations registers a constructor of probes registrations, returning a []ProbeRegistration,
+// for probes built at runtime, like from config.
+func AsProbeRegistrations(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"healthcheck-probes-registrations,flatten"`),
+		),
+	)
+}
+
+// AsProbe registers a probe constructor, with options like the kinds the probe belongs to, for example
+// AsProbe(NewDBProbe, healthcheck.Readiness, healthcheck.Startup).
+func AsProbe(constructor any, options ...ProbeOption) fx.Option {
+	name := fmt.Sprintf(`name:"healthcheck-probe-%d"`, probesCount.Add(1))
+
 	return fx.Provide(
 		fx.Annotate(
 			constructor,
 			fx.As(new(Probe)),
-			fx.ResultTags(`group:"healthcheck-probes"`),
+			fx.ResultTags(name),
+		),
+		fx.Annotate(
+			func(probe Probe) ProbeRegistration {
+				return ProbeRegistration{
+					Probe:   probe,
+					Options: options,
+				}
+			},
+			fx.ParamTags(name),
+			fx.ResultTags(`group:"healthcheck-probes-registrations"`),
 		),
 	)
 }
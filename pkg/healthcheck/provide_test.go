@@ -0,0 +1,46 @@
+package healthcheck_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestAsProbe(t *testing.T) {
+	var registrations []healthcheck.ProbeRegistration
+
+	// probes of the same type, with their own options
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		healthcheck.AsProbe(func() *testProbe {
+			return &testProbe{name: "db"}
+		}, healthcheck.Readiness),
+		healthcheck.AsProbe(func() *testProbe {
+			return &testProbe{name: "cache"}
+		}, healthcheck.Liveness, healthcheck.NonCritical()),
+		fx.Invoke(fx.Annotate(
+			func(r []healthcheck.ProbeRegistration) {
+				registrations = r
+			},
+			fx.ParamTags(`group:"healthcheck-probes-registrations"`),
+		)),
+	)
+	app.RequireStart().RequireStop()
+
+	require.Len(t, registrations, 2)
+
+	checker := newChecker(t, healthcheck.CheckerSettings{Timeout: time.Second}, registrations...)
+
+	assert.Equal(t, []string{"db"}, slices.Collect(maps.Keys(checker.Check(context.Background(), healthcheck.Readiness).ProbeResults)))
+	assert.Equal(t, []string{"cache"}, slices.Collect(maps.Keys(checker.Check(context.Background(), healthcheck.Liveness).ProbeResults)))
+	assert.False(t, checker.Check(context.Background(), healthcheck.Liveness).ProbeResults["cache"].Critical)
+}
@@ -1,44 +1,110 @@
 package healthcheck
 
 import (
+	"encoding/json"
 	"net/http"
+	"slices"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
 	"github.com/labstack/echo/v4"
 )
 
 type Server struct {
-	httpServer *echo.Echo
+	httpServer  *echo.Echo
+	paths       []string
+	healthPaths []string
+	settings    Settings
 }
 
-func NewServer(config *config.Config, checker *Checker) *Server {
+func NewServer(config *config.Config, settings Settings, checker *Checker) *Server {
 	server := echo.New()
 	server.HideBanner = true
 
-	server.GET(config.GetStringOrDefault("healthcheck.httpserver.path", "/health"), func(c echo.Context) error {
+	version := config.GetString("app.version")
+
+	healthPaths := []string{
+		settings.HTTPServer.Path,
+		settings.HTTPServer.LivenessPath,
+		settings.HTTPServer.ReadinessPath,
+		settings.HTTPServer.StartupPath,
+	}
+
+	paths := slices.Clone(healthPaths)
+
+	server.GET(settings.HTTPServer.Path, checkHandler(checker, version))
+	server.GET(settings.HTTPServer.LivenessPath, checkHandler(checker, version, Liveness))
+	server.GET(settings.HTTPServer.ReadinessPath, checkHandler(checker, version, Readiness))
+	server.GET(settings.HTTPServer.StartupPath, checkHandler(checker, version, Startup))
+
+	// effective config introspection, with secret and sensitive values redacted, only on the server own address
+	if settings.Debug.Config.Enabled {
+		server.GET(settings.Debug.Config.Path, func(c echo.Context) error {
+			return c.JSON(http.StatusOK, config.Settings(c.QueryParam("key")))
+		})
+
+		paths = append(paths, settings.Debug.Config.Path)
+	}
+
+	return &Server{
+		httpServer:  server,
+		paths:       paths,
+		healthPaths: healthPaths,
+		settings:    settings,
+	}
+}
+
+// checkHandler responds with the check result in the format negotiated from the Accept header:
+// application/health+json, text/plain for the Prometheus format, or by default the CheckerResult JSON if verbose.
+func checkHandler(checker *Checker, version string, kinds ...Kind) echo.HandlerFunc {
+	return func(c echo.Context) error {
 		verbose := c.QueryParam("verbose")
 
-		res := checker.Check(c.Request().Context())
-		if !res.Healthy() {
-			if verbose == "" {
-				return c.NoContent(http.StatusInternalServerError)
-			}
+		res := checker.Check(c.Request().Context(), kinds...)
 
-			return c.JSON(http.StatusInternalServerError, res)
+		code := http.StatusOK
+		if res.Unhealthy() {
+			code = http.StatusInternalServerError
 		}
 
-		if verbose == "" {
-			return c.NoContent(http.StatusOK)
+		switch negotiate(c.Request().Header.Get(echo.HeaderAccept)) {
+		case MIMEApplicationHealthJSON:
+			c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationHealthJSON)
+			c.Response().WriteHeader(code)
+
+			return json.NewEncoder(c.Response()).Encode(NewHealthResponse(res, version, verbose != ""))
+		case "text/plain":
+			return c.Blob(code, MIMETextPlainPrometheus, []byte(PrometheusText(res)))
 		}
 
-		return c.JSON(http.StatusOK, res)
-	})
+		if verbose == "" {
+			return c.NoContent(code)
+		}
 
-	return &Server{
-		httpServer: server,
+		return c.JSON(code, res)
 	}
 }
 
 func (s *Server) HTTPServer() *echo.Echo {
 	return s.httpServer
 }
+
+// Paths returns the GET paths served by the server.
+func (s *Server) Paths() []string {
+	return s.paths
+}
+
+// MainPaths returns the health check GET paths to serve with the server handler on a main server with the main mount,
+// or none. With mcp, it returns them for the MCP streamable HTTP server, only if enabled with mount_mcp.
+// The debug endpoints are never served on a main server.
+func (s *Server) MainPaths(mcp bool) []string {
+	if !s.Mounted() || (mcp && !s.settings.MountMCP) {
+		return nil
+	}
+
+	return s.healthPaths
+}
+
+// Mounted returns true if the health checks are served by the main servers, with the main mount.
+func (s *Server) Mounted() bool {
+	return s.settings.Mount == MountMain
+}
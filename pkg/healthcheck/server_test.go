@@ -0,0 +1,95 @@
+package healthcheck_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerFormats(t *testing.T) {
+	cfg, err := config.NewConfig(config.WithValues(map[string]any{
+		"app.version": "1.2.3",
+	}))
+	require.NoError(t, err)
+
+	settings, err := config.Bind[healthcheck.Settings](cfg, healthcheck.ModuleName)
+	require.NoError(t, err)
+
+	checker := newChecker(
+		t,
+		healthcheck.CheckerSettings{Timeout: time.Second},
+		healthcheck.ProbeRegistration{Probe: &testProbe{name: "db"}},
+		healthcheck.ProbeRegistration{
+			Probe:   &testProbe{name: "cache", err: errors.New("cache error")},
+			Options: []healthcheck.ProbeOption{healthcheck.NonCritical()},
+		},
+	)
+
+	server := healthcheck.NewServer(cfg, settings, checker).HTTPServer()
+
+	check := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/health?verbose=true", nil)
+		req.Header.Set("Accept", accept)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := check("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"status":"degraded"`)
+
+	rec = check("application/json;q=0.5, application/health+json")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, healthcheck.MIMEApplicationHealthJSON, rec.Header().Get("Content-Type"))
+
+	var response healthcheck.HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "warn", response.Status)
+	assert.Equal(t, "1.2.3", response.Version)
+	assert.Equal(t, "pass", response.Checks["db:responseTime"][0].Status)
+	assert.Equal(t, "warn", response.Checks["cache:responseTime"][0].Status)
+	assert.Equal(t, "cache error", response.Checks["cache:responseTime"][0].Output)
+
+	rec = check("text/plain")
+	assert.Equal(t, healthcheck.MIMETextPlainPrometheus, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "healthcheck_status 0.5\n")
+	assert.Contains(t, rec.Body.String(), `healthcheck_probe_status{probe="cache"} 0.5`)
+	assert.Contains(t, rec.Body.String(), `healthcheck_probe_status{probe="db"} 1`)
+}
+
+func TestServerMainPaths(t *testing.T) {
+	newServer := func(values map[string]any) *healthcheck.Server {
+		cfg, err := config.NewConfig(config.WithValues(values))
+		require.NoError(t, err)
+
+		settings, err := config.Bind[healthcheck.Settings](cfg, healthcheck.ModuleName)
+		require.NoError(t, err)
+
+		return healthcheck.NewServer(cfg, settings, newChecker(t, healthcheck.CheckerSettings{Timeout: time.Second}))
+	}
+
+	// served on their own server
+	server := newServer(map[string]any{"healthcheck.debug.config.enabled": true})
+	assert.Empty(t, server.MainPaths(false))
+	assert.False(t, server.Mounted())
+	assert.Contains(t, server.Paths(), "/debug/config")
+
+	// mounted on the httpserver only, without the debug endpoints
+	server = newServer(map[string]any{"healthcheck.mount": healthcheck.MountMain, "healthcheck.debug.config.enabled": true})
+	assert.True(t, server.Mounted())
+	assert.Empty(t, server.MainPaths(true))
+	assert.Equal(t, []string{"/health", "/livez", "/readyz", "/startupz"}, server.MainPaths(false))
+	assert.Contains(t, server.Paths(), "/debug/config")
+}
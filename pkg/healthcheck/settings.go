@@ -0,0 +1,87 @@
+package healthcheck
+
+import "time"
+
+const (
+	MountServer = "server"
+	MountMain   = "main"
+)
+
+// Settings configures the health checks, served on their own HTTP server,
+// or mounted on the httpserver one (and the MCP streamable HTTP server one if MountMCP) with the main mount.
+type Settings struct {
+	Mount      string                   `mapstructure:"mount" default:"server" validate:"oneof=server main"`
+	MountMCP   bool                     `mapstructure:"mount_mcp"`
+	HTTPServer HTTPServerSettings       `mapstructure:"httpserver"`
+	Checker    CheckerSettings          `mapstructure:"checker"`
+	Probes     map[string]ProbeSettings `mapstructure:"probes" validate:"dive"`
+	Debug      DebugSettings            `mapstructure:"debug"`
+}
+
+type HTTPServerSettings struct {
+	Address       string `mapstructure:"address" default:":8080" validate:"required"`
+	Path          string `mapstructure:"path" default:"/health" validate:"required,startswith=/"`
+	LivenessPath  string `mapstructure:"liveness_path" default:"/livez" validate:"required,startswith=/"`
+	ReadinessPath string `mapstructure:"readiness_path" default:"/readyz" validate:"required,startswith=/"`
+	StartupPath   string `mapstructure:"startup_path" default:"/startupz" validate:"required,startswith=/"`
+}
+
+// CheckerSettings configures the probes default timeout, and the background probing interval (disabled if 0).
+type CheckerSettings struct {
+	Timeout  time.Duration `mapstructure:"timeout" default:"5s" validate:"gt=0"`
+	Interval time.Duration `mapstructure:"interval" default:"0s" validate:"gte=0"`
+}
+
+// ProbeSettings configures a built-in probe of the probes package, by type:
+//   - http: requests the url with the method (GET by default), and expects the status (200 by default)
+//   - tcp: dials the address
+//   - disk: expects the path (/ by default) file system to have min_free_bytes and min_free_percent free space
+//   - goroutines: expects at most max_goroutines goroutines
+//   - heap: expects at most max_heap_bytes heap bytes
+//   - db: pings the default db connection, and expects at most max_pool_usage (1 by default) of its max open connections in use,
+//     registered by the db module
+//
+// The probes are critical by default, and belong to the readiness checks if no kind is set.
+type ProbeSettings struct {
+	Type           string        `mapstructure:"type" validate:"required,oneof=http tcp disk goroutines heap db"`
+	Kinds          []string      `mapstructure:"kinds" validate:"dive,oneof=liveness readiness startup"`
+	Timeout        time.Duration `mapstructure:"timeout" validate:"gte=0"`
+	Critical       *bool         `mapstructure:"critical"`
+	URL            string        `mapstructure:"url" validate:"required_if=Type http,omitempty,url"`
+	Method         string        `mapstructure:"method"`
+	ExpectedStatus int           `mapstructure:"expected_status" validate:"omitempty,gte=100,lte=599"`
+	Address        string        `mapstructure:"address" validate:"required_if=Type tcp"`
+	Path           string        `mapstructure:"path"`
+	MinFreeBytes   uint64        `mapstructure:"min_free_bytes"`
+	MinFreePercent float64       `mapstructure:"min_free_percent" validate:"gte=0,lte=100"`
+	MaxGoroutines  int           `mapstructure:"max_goroutines" validate:"required_if=Type goroutines,gte=0"`
+	MaxHeapBytes   uint64        `mapstructure:"max_heap_bytes" validate:"required_if=Type heap"`
+	MaxPoolUsage   float64       `mapstructure:"max_pool_usage" validate:"gte=0,lte=1"`
+}
+
+// Options returns the probe options of the settings.
+func (s ProbeSettings) Options() []ProbeOption {
+	var options []ProbeOption
+	for _, kind := range s.Kinds {
+		options = append(options, Kind(kind))
+	}
+
+	if s.Timeout > 0 {
+		options = append(options, WithTimeout(s.Timeout))
+	}
+
+	if s.Critical != nil && !*s.Critical {
+		options = append(options, NonCritical())
+	}
+
+	return options
+}
+
+type DebugSettings struct {
+	Config DebugConfigSettings `mapstructure:"config"`
+}
+
+type DebugConfigSettings struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path" default:"/debug/config" validate:"required,startswith=/"`
+}
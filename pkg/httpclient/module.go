@@ -19,6 +19,7 @@ const (
 
 var Module = fx.Module(
 	ModuleName,
+	config.AsSchema[Settings](ModuleName),
 	fx.Provide(
 		fx.Annotate(ProvideTransport, fx.As(fx.Self()), fx.As(new(http.RoundTripper))),
 		ProvideClient,
@@ -52,8 +53,17 @@ type ProvideClientParams struct {
 }
 
 func ProvideClient(params ProvideClientParams) *http.Client {
+	transport := NewTimeoutTransport(
+		params.RoundTripper,
+		params.Config.GetDurationOrDefault("httpclient.timeout", DefaultTimeout),
+	)
+
+	// the timeout is held by the transport, so it can follow config reloads
+	params.Config.OnChange("httpclient.timeout", func(_, _ any) {
+		transport.SetTimeout(params.Config.GetDurationOrDefault("httpclient.timeout", DefaultTimeout))
+	})
+
 	return &http.Client{
-		Transport: params.RoundTripper,
-		Timeout:   params.Config.GetDurationOrDefault("httpclient.timeout", DefaultTimeout),
+		Transport: transport,
 	}
 }
@@ -0,0 +1,7 @@
+package httpclient
+
+import "time"
+
+type Settings struct {
+	Timeout time.Duration `mapstructure:"timeout" default:"30s" validate:"gte=0"`
+}
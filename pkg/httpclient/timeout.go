@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// TimeoutTransport applies a timeout to each request, which can be changed at runtime contrary to http.Client.Timeout.
+// The timeout covers the whole exchange, until the response body is closed.
+type TimeoutTransport struct {
+	transport http.RoundTripper
+	timeout   atomic.Int64
+}
+
+func NewTimeoutTransport(transport http.RoundTripper, timeout time.Duration) *TimeoutTransport {
+	t := &TimeoutTransport{
+		transport: transport,
+	}
+
+	t.SetTimeout(timeout)
+
+	return t
+}
+
+func (t *TimeoutTransport) SetTimeout(timeout time.Duration) {
+	t.timeout.Store(int64(timeout))
+}
+
+func (t *TimeoutTransport) Timeout() time.Duration {
+	return time.Duration(t.timeout.Load())
+}
+
+func (t *TimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.Timeout()
+	if timeout <= 0 {
+		return t.transport.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+
+	res, err := t.transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	res.Body = &cancelOnCloseBody{
+		ReadCloser: res.Body,
+		cancel:     cancel,
+	}
+
+	return res, nil
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
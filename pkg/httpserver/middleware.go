@@ -0,0 +1,56 @@
+package httpserver
+
+import (
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// baggageValuePattern is the charset of the headers values added to the baggage, like ids, names or emails.
+var baggageValuePattern = regexp.MustCompile(`^[A-Za-z0-9._:@-]+$`)
+
+// NewBaggageMiddleware adds the values of the request headers to the request context baggage, if enabled,
+// the settings headers mapping the headers names to the baggage members keys.
+// The values are set by the clients and untrusted: they are ignored if longer than the max length or outside of
+// the [A-Za-z0-9._:@-] charset, and must not be used for authorization.
+func NewBaggageMiddleware(settings BaggageSettings) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !settings.Enabled || len(settings.Headers) == 0 {
+				return next(c)
+			}
+
+			req := c.Request()
+			bag := baggage.FromContext(req.Context())
+
+			for header, key := range settings.Headers {
+				value := req.Header.Get(header)
+				if value == "" {
+					continue
+				}
+
+				if len(value) > settings.MaxLength || !baggageValuePattern.MatchString(value) {
+					c.Logger().Warnf("cannot add header %s to baggage: invalid value", header)
+
+					continue
+				}
+
+				member, err := baggage.NewMemberRaw(key, value)
+				if err != nil {
+					c.Logger().Warnf("cannot add header %s to baggage: %v", header, err)
+
+					continue
+				}
+
+				if bag, err = bag.SetMember(member); err != nil {
+					c.Logger().Warnf("cannot add header %s to baggage: %v", header, err)
+				}
+			}
+
+			c.SetRequest(req.WithContext(baggage.ContextWithBaggage(req.Context(), bag)))
+
+			return next(c)
+		}
+	}
+}
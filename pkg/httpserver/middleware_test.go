@@ -0,0 +1,65 @@
+package httpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/httpserver"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// serve returns the baggage of a request with the headers, through the middleware.
+func serve(settings httpserver.BaggageSettings, headers map[string]string) baggage.Baggage {
+	var bag baggage.Baggage
+
+	server := echo.New()
+	server.Use(httpserver.NewBaggageMiddleware(settings))
+	server.GET("/", func(c echo.Context) error {
+		bag = baggage.FromContext(c.Request().Context())
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	server.ServeHTTP(httptest.NewRecorder(), req)
+
+	return bag
+}
+
+func TestBaggageMiddleware(t *testing.T) {
+	settings := httpserver.BaggageSettings{
+		Enabled:   true,
+		Headers:   map[string]string{"X-User-Id": "user.id", "X-Tenant-Id": "tenant.id"},
+		MaxLength: 16,
+	}
+
+	t.Run("maps the headers to the baggage", func(t *testing.T) {
+		bag := serve(settings, map[string]string{"X-User-Id": "alice@oryn.dev", "X-Tenant-Id": "oryn"})
+
+		assert.Equal(t, "alice@oryn.dev", bag.Member("user.id").Value())
+		assert.Equal(t, "oryn", bag.Member("tenant.id").Value())
+	})
+
+	t.Run("ignores the headers if disabled", func(t *testing.T) {
+		disabled := settings
+		disabled.Enabled = false
+
+		bag := serve(disabled, map[string]string{"X-User-Id": "alice"})
+
+		assert.Equal(t, 0, bag.Len())
+	})
+
+	t.Run("ignores the invalid values", func(t *testing.T) {
+		bag := serve(settings, map[string]string{"X-User-Id": strings.Repeat("a", 17), "X-Tenant-Id": "oryn,admin=true"})
+
+		assert.Equal(t, 0, bag.Len())
+	})
+}
@@ -2,9 +2,12 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 	"go.opentelemetry.io/otel/metric"
@@ -17,6 +20,7 @@ const ModuleName = "httpserver"
 
 var Module = fx.Module(
 	ModuleName,
+	config.AsSchema[Settings](ModuleName),
 	fx.Provide(
 		ProvideRegistry,
 		ProvideServer,
@@ -44,43 +48,70 @@ type ProvideServerParams struct {
 	TracerProvider trace.TracerProvider
 	MeterProvider  metric.MeterProvider
 	Registry       *Registry
+	Routes         []Route `group:"httpserver-routes"`
 }
 
 func ProvideServer(params ProvideServerParams) (*echo.Echo, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
 	server := echo.New()
 	server.HideBanner = true
 
-	err := params.Registry.Register(server)
+	err = params.Registry.Register(server)
 	if err != nil {
 		return nil, err
 	}
 
+	untraced := make(map[string]bool)
+	for _, route := range params.Routes {
+		server.Add(route.Method, route.Path, route.Handler)
+
+		if route.Untraced {
+			untraced[route.Method+" "+route.Path] = true
+		}
+	}
+
 	server.Use(otelecho.Middleware(
 		params.Config.GetString("app.name"),
 		otelecho.WithTracerProvider(params.TracerProvider),
 		otelecho.WithMeterProvider(params.MeterProvider),
 		otelecho.WithPropagators(params.Propagator),
+		otelecho.WithSkipper(func(c echo.Context) bool {
+			return untraced[c.Request().Method+" "+c.Path()]
+		}),
 	))
 
+	// after the propagated baggage extraction
+	server.Use(NewBaggageMiddleware(settings.Baggage))
+
 	return server, nil
 }
 
 func RunServer() fx.Option {
 	return fx.Invoke(
 		func(
-			lifecycle fx.Lifecycle,
+			lc fx.Lifecycle,
+			manager *lifecycle.Manager,
 			shutdown fx.Shutdowner,
-			config *config.Config,
+			cfg *config.Config,
 			logger *slog.Logger,
 			server *echo.Echo,
-		) {
-			address := config.GetStringOrDefault("httpserver.address", ":8080")
+		) error {
+			settings, err := config.Bind[Settings](cfg, ModuleName)
+			if err != nil {
+				return err
+			}
+
+			address := settings.Address
 
-			lifecycle.Append(fx.Hook{
+			lc.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
 					go func() {
 						err := server.Start(address)
-						if err != nil {
+						if err != nil && !errors.Is(err, http.ErrServerClosed) {
 
 							logger.ErrorContext(ctx, "failed to start HTTP server", "error", err, "address", address)
 
@@ -92,17 +123,20 @@ func RunServer() fx.Option {
 
 					return nil
 				},
-				OnStop: func(ctx context.Context) error {
-					err := server.Shutdown(ctx)
-					if err != nil {
-						logger.ErrorContext(ctx, "failed to stop HTTP server", "error", err)
+			})
 
-						return err
-					}
+			manager.OnStop(lifecycle.PhaseDrain, "HTTP server", func(ctx context.Context) error {
+				err := server.Shutdown(ctx)
+				if err != nil {
+					logger.ErrorContext(ctx, "failed to stop HTTP server", "error", err)
 
-					return nil
-				},
+					return err
+				}
+
+				return nil
 			})
+
+			return nil
 		},
 	)
 }
@@ -3,9 +3,29 @@ package httpserver
 import (
 	"reflect"
 
+	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
 )
 
+// Route is a route registered on the server outside of the handlers registry, like the health check routes.
+// An untraced route is excluded from the server tracing.
+type Route struct {
+	Method   string
+	Path     string
+	Handler  echo.HandlerFunc
+	Untraced bool
+}
+
+// AsRoutes registers a constructor of routes, returning a []Route.
+func AsRoutes(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"httpserver-routes,flatten"`),
+		),
+	)
+}
+
 type HandlerDefinition struct {
 	Method string
 	Path   string
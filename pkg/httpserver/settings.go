@@ -0,0 +1,15 @@
+package httpserver
+
+type Settings struct {
+	Address string          `mapstructure:"address" default:":8080" validate:"required"`
+	Baggage BaggageSettings `mapstructure:"baggage"`
+}
+
+// BaggageSettings maps request headers names to the baggage members to set from their values, if enabled.
+// The values are untrusted since set by the clients, they are bounded by the max length and a restricted charset,
+// see NewBaggageMiddleware.
+type BaggageSettings struct {
+	Enabled   bool              `mapstructure:"enabled"`
+	Headers   map[string]string `mapstructure:"headers"`
+	MaxLength int               `mapstructure:"max_length" default:"64" validate:"gt=0"`
+}
@@ -0,0 +1,130 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Phase is a shutdown phase, the phases are stopped in their declaration order.
+type Phase int
+
+const (
+	// PhaseUnready marks the application as not ready to receive traffic.
+	PhaseUnready Phase = iota
+	// PhaseDrain drains the servers connections.
+	PhaseDrain
+	// PhaseWorkers stops the workers.
+	PhaseWorkers
+	// PhaseTelemetry flushes and shuts down the telemetry providers.
+	PhaseTelemetry
+	// PhaseClose closes the remaining resources, like the db connections.
+	PhaseClose
+)
+
+var phases = []Phase{PhaseUnready, PhaseDrain, PhaseWorkers, PhaseTelemetry, PhaseClose}
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseUnready:
+		return "unready"
+	case PhaseDrain:
+		return "drain"
+	case PhaseWorkers:
+		return "workers"
+	case PhaseTelemetry:
+		return "telemetry"
+	case PhaseClose:
+		return "close"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+type hook struct {
+	name   string
+	onStop func(ctx context.Context) error
+}
+
+// Manager runs the registered stop hooks by phase, in order.
+// The hooks of a same phase are run concurrently.
+type Manager struct {
+	mu           sync.Mutex
+	settings     Settings
+	hooks        map[Phase][]hook
+	preStopDelay time.Duration
+}
+
+func NewManager(settings Settings) *Manager {
+	return &Manager{
+		settings: settings,
+		hooks:    make(map[Phase][]hook),
+	}
+}
+
+func (m *Manager) Settings() Settings {
+	return m.settings
+}
+
+// OnStop registers a named stop hook in a phase.
+func (m *Manager) OnStop(phase Phase, name string, onStop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks[phase] = append(m.hooks[phase], hook{
+		name:   name,
+		onStop: onStop,
+	})
+}
+
+// DelayStop waits for the delay after the unready phase on Stop, so the load balancers can deregister the application
+// before its connections are drained.
+func (m *Manager) DelayStop(delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.preStopDelay = delay
+}
+
+// Stop runs all phases, even if some hooks fail, and returns the hooks errors.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := m.hooks
+	preStopDelay := m.preStopDelay
+	m.hooks = make(map[Phase][]hook)
+	m.mu.Unlock()
+
+	var errs []error
+
+	for _, phase := range phases {
+		errs = append(errs, stopPhase(ctx, phase, hooks[phase]))
+
+		if phase == PhaseUnready && preStopDelay > 0 {
+			select {
+			case <-time.After(preStopDelay):
+			case <-ctx.Done():
+				errs = append(errs, fmt.Errorf("pre stop delay: %w", ctx.Err()))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func stopPhase(ctx context.Context, phase Phase, hooks []hook) error {
+	errs := make([]error, len(hooks))
+
+	var wg sync.WaitGroup
+	for i, h := range hooks {
+		wg.Go(func() {
+			if err := h.onStop(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s phase: %s: %w", phase, h.name, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
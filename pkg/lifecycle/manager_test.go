@@ -0,0 +1,45 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerStop(t *testing.T) {
+	manager := lifecycle.NewManager(lifecycle.Settings{})
+	manager.DelayStop(10 * time.Millisecond)
+
+	var mu sync.Mutex
+	var stopped []string
+
+	register := func(phase lifecycle.Phase, name string, err error) {
+		manager.OnStop(phase, name, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			stopped = append(stopped, name)
+
+			return err
+		})
+	}
+
+	// registered out of order
+	register(lifecycle.PhaseClose, "db", nil)
+	register(lifecycle.PhaseTelemetry, "otel", nil)
+	register(lifecycle.PhaseWorkers, "workers", errors.New("worker failure"))
+	register(lifecycle.PhaseDrain, "http", nil)
+	register(lifecycle.PhaseUnready, "healthcheck", nil)
+
+	start := time.Now()
+	err := manager.Stop(context.Background())
+
+	assert.EqualError(t, err, "workers phase: workers: worker failure")
+	assert.Equal(t, []string{"healthcheck", "http", "workers", "otel", "db"}, stopped)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
@@ -0,0 +1,37 @@
+package lifecycle
+
+import (
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"go.uber.org/fx"
+)
+
+const ModuleName = "lifecycle"
+
+var Module = fx.Module(
+	ModuleName,
+	config.AsSchema[Settings](ModuleName),
+	fx.Provide(
+		ProvideManager,
+	),
+)
+
+type ProvideManagerParams struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+}
+
+func ProvideManager(params ProvideManagerParams) (*Manager, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := NewManager(settings)
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStop: manager.Stop,
+	})
+
+	return manager, nil
+}
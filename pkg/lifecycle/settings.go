@@ -0,0 +1,9 @@
+package lifecycle
+
+import "time"
+
+type Settings struct {
+	StartTimeout time.Duration `mapstructure:"start_timeout" default:"15s" validate:"gt=0"`
+	StopTimeout  time.Duration `mapstructure:"stop_timeout" default:"30s" validate:"gt=0"`
+	PreStopDelay time.Duration `mapstructure:"pre_stop_delay" default:"0s" validate:"gte=0"`
+}
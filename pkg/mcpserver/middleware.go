@@ -2,31 +2,26 @@ package mcpserver
 
 import (
 	"context"
-	"fmt"
+	"reflect"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel/propagation"
 )
 
+// NewReceivingMiddleware extracts the trace context and baggage propagated in the requests _meta.
 func NewReceivingMiddleware(propagator propagation.TextMapPropagator) mcp.Middleware {
 	return func(mh mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+			// the params can be a typed nil pointer, for methods without params
 			params := req.GetParams()
-			meta := params.GetMeta()
-
-			fmt.Printf("*** params %+#v\n", params)
-			fmt.Printf("*** meta %+#v\n", meta)
-
-			if meta == nil {
-				meta = make(map[string]any)
-				params.SetMeta(meta)
+			if value := reflect.ValueOf(params); params != nil && !(value.Kind() == reflect.Pointer && value.IsNil()) {
+				if meta := params.GetMeta(); meta != nil {
+					ctx = propagator.Extract(ctx, stringAnyMapCarrier{meta})
+				}
 			}
 
-			propagator.Inject(ctx, stringAnyMapCarrier{meta})
-
 			return mh(ctx, method, req)
 		}
-
 	}
 }
 
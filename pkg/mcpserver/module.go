@@ -2,10 +2,13 @@ package mcpserver
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net"
+	"net/http"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
@@ -17,6 +20,7 @@ const ModuleName = "mcpserver"
 
 var Module = fx.Module(
 	ModuleName,
+	config.AsSchema[Settings](ModuleName),
 	fx.Provide(
 		ProvideRegistry,
 		ProvideServer,
@@ -74,27 +78,36 @@ type ProvideStreamableHTTPHandlerParams struct {
 	Propagator     propagation.TextMapPropagator
 	TracerProvider trace.TracerProvider
 	MeterProvider  metric.MeterProvider
+	Handlers       []HTTPHandler `group:"mcpserver-http-handlers"`
 }
 
-func ProvideStreamableHTTPServer(params ProvideStreamableHTTPHandlerParams) *StreamableHTTPServer {
+func ProvideStreamableHTTPServer(params ProvideStreamableHTTPHandlerParams) (*StreamableHTTPServer, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
 	return NewStreamableHTTPServer(
-		params.Config,
+		params.Config.GetString("app.name"),
+		settings,
 		params.Server,
 		params.Propagator,
 		params.TracerProvider,
 		params.MeterProvider,
-	)
+		params.Handlers...,
+	), nil
 }
 
 func RunStreamableHTTPServer() fx.Option {
 	return fx.Invoke(
 		func(
-			lifecycle fx.Lifecycle,
+			lc fx.Lifecycle,
+			manager *lifecycle.Manager,
 			shutdown fx.Shutdowner,
 			logger *slog.Logger,
 			server *StreamableHTTPServer,
 		) {
-			lifecycle.Append(fx.Hook{
+			lc.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
 					addr := server.HTTPServer().Addr
 
@@ -110,7 +123,7 @@ func RunStreamableHTTPServer() fx.Option {
 
 					go func() {
 						err := server.HTTPServer().Serve(lis)
-						if err != nil {
+						if err != nil && !errors.Is(err, http.ErrServerClosed) {
 							logger.ErrorContext(ctx, "failed to start MCP streamable HTTP server",
 								"error", err,
 								"address", addr,
@@ -124,16 +137,17 @@ func RunStreamableHTTPServer() fx.Option {
 
 					return nil
 				},
-				OnStop: func(ctx context.Context) error {
-					err := server.HTTPServer().Shutdown(ctx)
-					if err != nil {
-						logger.ErrorContext(ctx, "failed to stop MCP streamable HTTP server", "error", err)
+			})
 
-						return err
-					}
+			manager.OnStop(lifecycle.PhaseDrain, "MCP streamable HTTP server", func(ctx context.Context) error {
+				err := server.HTTPServer().Shutdown(ctx)
+				if err != nil {
+					logger.ErrorContext(ctx, "failed to stop MCP streamable HTTP server", "error", err)
 
-					return nil
-				},
+					return err
+				}
+
+				return nil
 			})
 		},
 	)
@@ -1,9 +1,27 @@
 package mcpserver
 
 import (
+	"net/http"
+
 	"go.uber.org/fx"
 )
 
+// HTTPHandler is a handler registered on the streamable HTTP server mux, next to the MCP handler.
+type HTTPHandler struct {
+	Pattern string
+	Handler http.Handler
+}
+
+// AsHTTPHandlers registers a constructor of handlers, returning a []HTTPHandler.
+func AsHTTPHandlers(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"mcpserver-http-handlers,flatten"`),
+		),
+	)
+}
+
 func AsCapability(constructor any) fx.Option {
 	return fx.Provide(
 		fx.Annotate(
@@ -3,7 +3,6 @@ package mcpserver
 import (
 	"net/http"
 
-	"github.com/go-oryn/oryn-sandbox/pkg/config"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel/metric"
@@ -16,11 +15,13 @@ type StreamableHTTPServer struct {
 }
 
 func NewStreamableHTTPServer(
-	config *config.Config,
+	serverName string,
+	settings Settings,
 	mcpServer *mcp.Server,
 	propagator propagation.TextMapPropagator,
 	tracerProvider trace.TracerProvider,
 	meterProvider metric.MeterProvider,
+	handlers ...HTTPHandler,
 ) *StreamableHTTPServer {
 	handler := mcp.NewStreamableHTTPHandler(
 		func(r *http.Request) *mcp.Server {
@@ -34,19 +35,24 @@ func NewStreamableHTTPServer(
 
 	mux := http.NewServeMux()
 	mux.Handle(
-		config.GetStringOrDefault("mcpserver.transport.options.path", "/mcp"),
+		settings.Transport.Options.Path,
 		otelhttp.NewHandler(
 			handler,
 			"MCP Request",
-			otelhttp.WithServerName(config.GetString("app.name")),
+			otelhttp.WithServerName(serverName),
 			otelhttp.WithPropagators(propagator),
 			otelhttp.WithTracerProvider(tracerProvider),
 			otelhttp.WithMeterProvider(meterProvider),
 		),
 	)
 
+	// the additional handlers are not traced
+	for _, h := range handlers {
+		mux.Handle(h.Pattern, h.Handler)
+	}
+
 	httpServer := &http.Server{
-		Addr:    config.GetStringOrDefault("mcpserver.transport.options.address", ":8080"),
+		Addr:    settings.Transport.Options.Address,
 		Handler: mux,
 	}
 
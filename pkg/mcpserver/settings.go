@@ -0,0 +1,15 @@
+package mcpserver
+
+type Settings struct {
+	Transport TransportSettings `mapstructure:"transport"`
+}
+
+type TransportSettings struct {
+	Type    string                   `mapstructure:"type" default:"streamable-http" validate:"oneof=streamable-http"`
+	Options TransportOptionsSettings `mapstructure:"options"`
+}
+
+type TransportOptionsSettings struct {
+	Address string `mapstructure:"address" default:":8080" validate:"required"`
+	Path    string `mapstructure:"path" default:"/mcp" validate:"required,startswith=/"`
+}
@@ -6,19 +6,31 @@ import (
 )
 
 type LeveledHandler struct {
-	level   slog.Level
+	level   *slog.LevelVar
 	handler slog.Handler
 }
 
 func NewLeveledHandler(level slog.Level, handler slog.Handler) *LeveledHandler {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(level)
+
 	return &LeveledHandler{
-		level:   level,
+		level:   levelVar,
 		handler: handler,
 	}
 }
 
+// SetLevel changes the minimum level of the handler, and of all the handlers derived from it.
+func (h *LeveledHandler) SetLevel(level slog.Level) {
+	h.level.Set(level)
+}
+
+func (h *LeveledHandler) Level() slog.Level {
+	return h.level.Level()
+}
+
 func (h *LeveledHandler) Enabled(_ context.Context, level slog.Level) bool {
-	return level >= h.level
+	return level >= h.level.Level()
 }
 
 func (h *LeveledHandler) Handle(ctx context.Context, record slog.Record) error {
@@ -26,9 +38,9 @@ func (h *LeveledHandler) Handle(ctx context.Context, record slog.Record) error {
 }
 
 func (h *LeveledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return NewLeveledHandler(h.level, h.handler.WithAttrs(attrs))
+	return &LeveledHandler{level: h.level, handler: h.handler.WithAttrs(attrs)}
 }
 
 func (h *LeveledHandler) WithGroup(name string) slog.Handler {
-	return NewLeveledHandler(h.level, h.handler.WithGroup(name))
+	return &LeveledHandler{level: h.level, handler: h.handler.WithGroup(name)}
 }
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
@@ -26,10 +27,10 @@ var Module = fx.Module(
 
 type ProvideLoggerProviderParams struct {
 	fx.In
-	Lifecycle fx.Lifecycle
-	Config    *config.Config
-	Resource  *resource.Resource
-	Options   []sdklog.LoggerProviderOption `group:"otel-log-provider-options"`
+	Manager  *lifecycle.Manager
+	Config   *config.Config
+	Resource *resource.Resource
+	Options  []sdklog.LoggerProviderOption `group:"otel-log-provider-options"`
 }
 
 func ProvideLoggerProvider(params ProvideLoggerProviderParams) (*sdklog.LoggerProvider, error) {
@@ -43,15 +44,13 @@ func ProvideLoggerProvider(params ProvideLoggerProviderParams) (*sdklog.LoggerPr
 
 	global.SetLoggerProvider(lp)
 
-	params.Lifecycle.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			err := lp.ForceFlush(ctx)
-			if err != nil {
-				return err
-			}
+	params.Manager.OnStop(lifecycle.PhaseTelemetry, "otel logger provider", func(ctx context.Context) error {
+		err := lp.ForceFlush(ctx)
+		if err != nil {
+			return err
+		}
 
-			return lp.Shutdown(ctx)
-		},
+		return lp.Shutdown(ctx)
 	})
 
 	return lp, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -23,10 +24,10 @@ var Module = fx.Module(
 
 type ProvideMeterProviderParams struct {
 	fx.In
-	Lifecycle fx.Lifecycle
-	Config    *config.Config
-	Resource  *resource.Resource
-	Options   []sdkmetric.Option `group:"otel-metric-provider-options"`
+	Manager  *lifecycle.Manager
+	Config   *config.Config
+	Resource *resource.Resource
+	Options  []sdkmetric.Option `group:"otel-metric-provider-options"`
 }
 
 func ProvideMeterProvider(params ProvideMeterProviderParams) (*sdkmetric.MeterProvider, error) {
@@ -40,15 +41,13 @@ func ProvideMeterProvider(params ProvideMeterProviderParams) (*sdkmetric.MeterPr
 
 	otel.SetMeterProvider(mp)
 
-	params.Lifecycle.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			err := mp.ForceFlush(ctx)
-			if err != nil {
-				return err
-			}
+	params.Manager.OnStop(lifecycle.PhaseTelemetry, "otel meter provider", func(ctx context.Context) error {
+		err := mp.ForceFlush(ctx)
+		if err != nil {
+			return err
+		}
 
-			return mp.Shutdown(ctx)
-		},
+		return mp.Shutdown(ctx)
 	})
 
 	return mp, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
 	"github.com/go-oryn/oryn-sandbox/pkg/otel/log"
 	"github.com/go-oryn/oryn-sandbox/pkg/otel/metric"
 	"github.com/go-oryn/oryn-sandbox/pkg/otel/trace"
@@ -24,6 +25,10 @@ var Module = fx.Module(
 	log.Module,
 	metric.Module,
 	trace.Module,
+	// config
+	config.AsSchema[LogSettings]("log"),
+	config.AsSchema[MetricSettings]("metric"),
+	config.AsSchema[TraceSettings]("trace"),
 	// common dependencies
 	fx.Provide(
 		ProvideResource,
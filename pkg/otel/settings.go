@@ -0,0 +1,23 @@
+package otel
+
+import "time"
+
+type LogSettings struct {
+	Level     string                      `mapstructure:"level" default:"info" validate:"oneof=debug info warn warning error"`
+	Source    bool                        `mapstructure:"source"`
+	Exporters map[string]ExporterSettings `mapstructure:"exporters" validate:"dive,keys,oneof=stdout otlp_grpc,endkeys"`
+}
+
+type MetricSettings struct {
+	Interval  time.Duration               `mapstructure:"interval" default:"60s" validate:"gt=0"`
+	Exporters map[string]ExporterSettings `mapstructure:"exporters" validate:"dive,keys,oneof=stdout otlp_grpc,endkeys"`
+}
+
+type TraceSettings struct {
+	Exporters map[string]ExporterSettings `mapstructure:"exporters" validate:"dive,keys,oneof=stdout otlp_grpc,endkeys"`
+}
+
+type ExporterSettings struct {
+	Enabled bool           `mapstructure:"enabled"`
+	Options map[string]any `mapstructure:"options"`
+}
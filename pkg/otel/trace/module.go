@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -23,10 +24,10 @@ var Module = fx.Module(
 
 type ProvideTracerProviderParams struct {
 	fx.In
-	Lifecycle fx.Lifecycle
-	Config    *config.Config
-	Resource  *resource.Resource
-	Options   []sdktrace.TracerProviderOption `group:"otel-trace-provider-options"`
+	Manager  *lifecycle.Manager
+	Config   *config.Config
+	Resource *resource.Resource
+	Options  []sdktrace.TracerProviderOption `group:"otel-trace-provider-options"`
 }
 
 func ProvideTracerProvider(params ProvideTracerProviderParams) (*sdktrace.TracerProvider, error) {
@@ -40,15 +41,13 @@ func ProvideTracerProvider(params ProvideTracerProviderParams) (*sdktrace.Tracer
 
 	otel.SetTracerProvider(tp)
 
-	params.Lifecycle.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			err := tp.ForceFlush(ctx)
-			if err != nil {
-				return err
-			}
+	params.Manager.OnStop(lifecycle.PhaseTelemetry, "otel tracer provider", func(ctx context.Context) error {
+		err := tp.ForceFlush(ctx)
+		if err != nil {
+			return err
+		}
 
-			return tp.Shutdown(ctx)
-		},
+		return tp.Shutdown(ctx)
 	})
 
 	return tp, nil
@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	eventIDKey     = attribute.Key("outbox.event.id")
+	eventTopicKey  = attribute.Key("outbox.event.topic")
+	eventStatusKey = attribute.Key("outbox.event.status")
+)
+
+type relayMetrics struct {
+	deliveries metric.Int64Counter
+	lag        metric.Float64Histogram
+}
+
+func newRelayMetrics(meter metric.Meter) (*relayMetrics, error) {
+	deliveries, err := meter.Int64Counter(
+		"outbox.event.deliveries",
+		metric.WithDescription("Number of outbox events delivery attempts, by resulting event status."),
+		metric.WithUnit("{delivery}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lag, err := meter.Float64Histogram(
+		"outbox.event.lag",
+		metric.WithDescription("Duration between the outbox events publication and delivery."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &relayMetrics{
+		deliveries: deliveries,
+		lag:        lag,
+	}, nil
+}
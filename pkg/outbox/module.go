@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+const ModuleName = "outbox"
+
+var Module = fx.Module(
+	ModuleName,
+	config.AsSchema[Settings](ModuleName),
+	fx.Provide(
+		ProvidePublisher,
+		ProvideSink,
+	),
+	worker.AsSingletonWorker(ProvideRelay),
+)
+
+type ProvidePublisherParams struct {
+	fx.In
+	Config     *config.Config
+	DB         *sql.DB
+	Propagator propagation.TextMapPropagator
+}
+
+func ProvidePublisher(params ProvidePublisherParams) (*Publisher, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPublisher(params.DB, params.Propagator, settings.Table), nil
+}
+
+type ProvideSinkParams struct {
+	fx.In
+	Config *config.Config
+	Logger *slog.Logger
+	Client *http.Client
+	Sinks  []Sink `group:"outbox-sinks"`
+}
+
+// ProvideSink provides the configured sink, a registered sink takes precedence over a built-in one with the same name.
+func ProvideSink(params ProvideSinkParams) (Sink, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sink := range params.Sinks {
+		if sink.Name() == settings.Sink.Type {
+			return sink, nil
+		}
+	}
+
+	switch settings.Sink.Type {
+	case "log":
+		return NewLogSink(params.Logger), nil
+	case "webhook":
+		return NewWebhookSink(params.Client, settings.Sink.URL, settings.Sink.Headers), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox sink %s", settings.Sink.Type)
+	}
+}
+
+type ProvideRelayParams struct {
+	fx.In
+	Config         *config.Config
+	DB             *sql.DB
+	Logger         *slog.Logger
+	Propagator     propagation.TextMapPropagator
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Sink           Sink
+}
+
+func ProvideRelay(params ProvideRelayParams) (*Relay, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRelay(
+		params.DB,
+		params.Logger,
+		params.TracerProvider.Tracer("github.com/go-oryn/oryn-sandbox/pkg/outbox"),
+		params.Propagator,
+		params.MeterProvider.Meter("github.com/go-oryn/oryn-sandbox/pkg/outbox"),
+		params.Sink,
+		settings,
+	)
+}
@@ -0,0 +1,14 @@
+package outbox
+
+import "go.uber.org/fx"
+
+// AsSink registers a sink constructor, selected with the outbox.sink.type config set to the sink name.
+func AsSink(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(Sink)),
+			fx.ResultTags(`group:"outbox-sinks"`),
+		),
+	)
+}
@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// ErrNoTx is returned when publishing an event outside of a transaction.
+var ErrNoTx = errors.New("no transaction in context, events must be published within db.Transactor.WithinTx")
+
+type PublishOption func(o *publishOptions)
+
+type publishOptions struct {
+	idempotencyKey string
+}
+
+// WithIdempotencyKey sets the event idempotency key, a random one is generated by default.
+// An event published again with the same key is ignored.
+func WithIdempotencyKey(key string) PublishOption {
+	return func(o *publishOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// Publisher writes the events in the outbox table, with the trace context of their publication.
+type Publisher struct {
+	db         *sql.DB
+	propagator propagation.TextMapPropagator
+	table      string
+}
+
+func NewPublisher(db *sql.DB, propagator propagation.TextMapPropagator, table string) *Publisher {
+	return &Publisher{
+		db:         db,
+		propagator: propagator,
+		table:      table,
+	}
+}
+
+// Publish writes an event of the topic, with its payload encoded to JSON, within the transaction of the context
+// (see db.Transactor): the event is delivered only if the transaction commits. It fails with ErrNoTx outside of one.
+func (p *Publisher) Publish(ctx context.Context, topic string, payload any, options ...PublishOption) error {
+	tx, ok := db.TxFromContext(ctx, p.db)
+	if !ok {
+		return fmt.Errorf("failed to publish %s event: %w", topic, ErrNoTx)
+	}
+
+	opts := publishOptions{
+		idempotencyKey: rand.Text(),
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event payload: %w", topic, err)
+	}
+
+	headers := propagation.MapCarrier{}
+	p.propagator.Inject(ctx, headers)
+
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event headers: %w", topic, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO %s (topic, idempotency_key, payload, headers, status) VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE id = id`,
+			p.table,
+		),
+		topic,
+		opts.idempotencyKey,
+		encodedPayload,
+		encodedHeaders,
+		StatusPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", topic, err)
+	}
+
+	return nil
+}
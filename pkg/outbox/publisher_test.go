@@ -0,0 +1,46 @@
+package outbox_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/go-oryn/oryn-sandbox/pkg/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestPublisher(t *testing.T) {
+	t.Run("publishes the events within the transaction of the context", func(t *testing.T) {
+		connector := dbtest.NewConnector()
+		sqlDB := sql.OpenDB(connector)
+		publisher := outbox.NewPublisher(sqlDB, propagation.TraceContext{}, "outbox_events")
+
+		err := db.NewTransactor(sqlDB, slog.New(slog.NewTextHandler(io.Discard, nil)), db.TxSettings{}).
+			WithinTx(context.Background(), func(ctx context.Context) error {
+				return publisher.Publish(ctx, "a", map[string]string{"name": "alice"}, outbox.WithIdempotencyKey("key"))
+			})
+		require.NoError(t, err)
+
+		execs := connector.Execs()
+		require.Len(t, execs, 1)
+		assert.Equal(t, "INSERT INTO outbox_events (topic, idempotency_key, payload, headers, status) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id", execs[0].Query)
+		assert.Equal(t, []driver.Value{"a", "key", []byte(`{"name":"alice"}`), []byte(`{}`), outbox.StatusPending}, execs[0].Args)
+	})
+
+	t.Run("fails outside of a transaction", func(t *testing.T) {
+		connector := dbtest.NewConnector()
+
+		err := outbox.NewPublisher(sql.OpenDB(connector), propagation.TraceContext{}, "outbox_events").
+			Publish(context.Background(), "a", map[string]string{"name": "alice"})
+
+		assert.ErrorIs(t, err, outbox.ErrNoTx)
+		assert.Empty(t, connector.Execs())
+	})
+}
@@ -0,0 +1,252 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type pendingEvent struct {
+	Event
+	headers  []byte
+	attempts int
+}
+
+var _ worker.Worker = (*Relay)(nil)
+
+// Relay is a worker delivering the pending events to the sink, in order within each topic:
+// an event waiting for its delivery retry holds back the next events of its topic, without holding back the other topics.
+// The order is the events ids order: the ids are assigned on insert and not on commit, so the events published by concurrent
+// transactions can be delivered out of order, the order within a topic is guaranteed only if its publications are serialized.
+// It is registered as a singleton worker, so a single relay delivers the events.
+type Relay struct {
+	db         *sql.DB
+	logger     *slog.Logger
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	metrics    *relayMetrics
+	sink       Sink
+	settings   Settings
+}
+
+func NewRelay(
+	db *sql.DB,
+	logger *slog.Logger,
+	tracer trace.Tracer,
+	propagator propagation.TextMapPropagator,
+	meter metric.Meter,
+	sink Sink,
+	settings Settings,
+) (*Relay, error) {
+	metrics, err := newRelayMetrics(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Relay{
+		db:         db,
+		logger:     logger,
+		tracer:     tracer,
+		propagator: propagator,
+		metrics:    metrics,
+		sink:       sink,
+		settings:   settings,
+	}, nil
+}
+
+func (r *Relay) Name() string {
+	return "outbox-relay"
+}
+
+// Run relays the pending events every poll interval, or right away while full batches are delivered.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.settings.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		delivered, err := r.relay(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if delivered == r.settings.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay delivers a batch of pending events, and returns the number of delivered ones.
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	events, err := r.pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	blocked := make(map[string]bool)
+
+	for _, event := range events {
+		if blocked[event.Topic] {
+			continue
+		}
+
+		ok, err := r.deliver(ctx, event)
+		if err != nil {
+			return delivered, err
+		}
+
+		if ok {
+			delivered++
+		} else {
+			blocked[event.Topic] = true
+		}
+	}
+
+	return delivered, nil
+}
+
+// pending selects the due pending events in their ids order, excluding the events held back by a previous event of their topic
+// waiting for its delivery retry, so the topics blocked on a retry do not fill the batches.
+func (r *Relay) pending(ctx context.Context) ([]pendingEvent, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT e.id, e.topic, e.idempotency_key, e.payload, e.headers, e.attempts,
+			TIMESTAMPDIFF(MICROSECOND, e.created_at, NOW(6))
+			FROM %[1]s e
+			WHERE e.status = ? AND e.next_attempt_at <= NOW(6) AND NOT EXISTS (
+				SELECT 1 FROM %[1]s b
+				WHERE b.topic = e.topic AND b.status = ? AND b.id < e.id AND b.next_attempt_at > NOW(6)
+			)
+			ORDER BY e.id LIMIT ?`,
+			r.settings.Table,
+		),
+		StatusPending,
+		StatusPending,
+		r.settings.BatchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select pending events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []pendingEvent
+
+	for rows.Next() {
+		var (
+			event pendingEvent
+			age   int64
+		)
+
+		err = rows.Scan(&event.ID, &event.Topic, &event.IdempotencyKey, &event.Payload, &event.headers, &event.attempts, &age)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pending event: %w", err)
+		}
+
+		event.CreatedAt = time.Now().Add(-time.Duration(age) * time.Microsecond)
+
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to select pending events: %w", err)
+	}
+
+	return events, nil
+}
+
+// deliver delivers the event in the trace it was published from, and returns true if delivered,
+// or an error if its delivery outcome could not be recorded.
+func (r *Relay) deliver(ctx context.Context, event pendingEvent) (bool, error) {
+	carrier := propagation.MapCarrier{}
+	if len(event.headers) > 0 {
+		if err := json.Unmarshal(event.headers, &carrier); err != nil {
+			r.logger.WarnContext(ctx, "failed to decode event headers", "id", event.ID, "topic", event.Topic, "error", err)
+		}
+	}
+
+	ctx, span := r.tracer.Start(
+		r.propagator.Extract(ctx, carrier),
+		"deliver "+event.Topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(eventIDKey.Int64(event.ID), eventTopicKey.String(event.Topic)),
+	)
+	defer span.End()
+
+	deliveryErr := r.sink.Deliver(ctx, event.Event)
+
+	attempts := event.attempts + 1
+
+	var (
+		status string
+		query  string
+		args   []any
+	)
+
+	switch {
+	case deliveryErr == nil:
+		status = StatusDelivered
+		query = "UPDATE %s SET status = ?, attempts = ?, delivered_at = NOW(6) WHERE id = ?"
+		args = []any{status, attempts, event.ID}
+
+		r.metrics.lag.Record(ctx, time.Since(event.CreatedAt).Seconds(), metric.WithAttributes(eventTopicKey.String(event.Topic)))
+	case attempts >= r.settings.MaxAttempts:
+		status = StatusDead
+		query = "UPDATE %s SET status = ?, attempts = ?, last_error = ? WHERE id = ?"
+		args = []any{status, attempts, deliveryErr.Error(), event.ID}
+
+		r.logger.ErrorContext(ctx, "event delivery failed, moved to dead state", "id", event.ID, "topic", event.Topic, "attempts", attempts, "error", deliveryErr)
+	default:
+		status = StatusPending
+		delay := r.backoff(attempts)
+		query = "UPDATE %s SET attempts = ?, last_error = ?, next_attempt_at = NOW(6) + INTERVAL ? MICROSECOND WHERE id = ?"
+		args = []any{attempts, deliveryErr.Error(), delay.Microseconds(), event.ID}
+
+		r.logger.WarnContext(ctx, "event delivery failed, retrying", "id", event.ID, "topic", event.Topic, "attempts", attempts, "delay", delay, "error", deliveryErr)
+	}
+
+	span.SetAttributes(eventStatusKey.String(status))
+	if deliveryErr != nil {
+		span.RecordError(deliveryErr)
+		span.SetStatus(codes.Error, deliveryErr.Error())
+	}
+
+	r.metrics.deliveries.Add(ctx, 1, metric.WithAttributes(eventTopicKey.String(event.Topic), eventStatusKey.String(status)))
+
+	// the delivery outcome is recorded even if the relay is stopping
+	_, err := r.db.ExecContext(context.WithoutCancel(ctx), fmt.Sprintf(query, r.settings.Table), args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to record event %d delivery: %w", event.ID, err)
+	}
+
+	return deliveryErr == nil, nil
+}
+
+// backoff returns the exponential delay before the delivery retry of an event, capped to the max backoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.settings.InitialBackoff
+	for i := 1; i < attempts && delay < r.settings.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, r.settings.MaxBackoff)
+}
@@ -0,0 +1,155 @@
+package outbox_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/go-oryn/oryn-sandbox/pkg/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// eventRow returns a pending event row, with its delivery attempts so far.
+func eventRow(id int64, topic string, attempts int64) []driver.Value {
+	return []driver.Value{id, topic, "key", []byte(`{}`), nil, attempts, int64(0)}
+}
+
+type testSink struct {
+	mu        sync.Mutex
+	delivered []int64
+	failing   map[int64]bool
+}
+
+func (s *testSink) Name() string {
+	return "test"
+}
+
+func (s *testSink) Deliver(_ context.Context, event outbox.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delivered = append(s.delivered, event.ID)
+
+	if s.failing[event.ID] {
+		return errors.New("failure")
+	}
+
+	return nil
+}
+
+// relay runs a relay until it recorded the statements count, and returns them with the first pending events query.
+func relay(t *testing.T, sink *testSink, statements int, events ...[]driver.Value) ([]dbtest.Statement, dbtest.Statement) {
+	t.Helper()
+
+	connector := dbtest.NewConnector(
+		dbtest.WithRows("SELECT", []string{"id", "topic", "idempotency_key", "payload", "headers", "attempts", "age"}, events...),
+	)
+
+	r, err := outbox.NewRelay(
+		sql.OpenDB(connector),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tracenoop.NewTracerProvider().Tracer("test"),
+		propagation.TraceContext{},
+		noop.NewMeterProvider().Meter("test"),
+		sink,
+		outbox.Settings{
+			Table:          "outbox_events",
+			PollInterval:   time.Millisecond,
+			BatchSize:      10,
+			MaxAttempts:    3,
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Minute,
+		},
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		done <- r.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(connector.Execs()) >= statements
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	return connector.Execs(), connector.Queries()[0]
+}
+
+func deliveredExec(id int64) dbtest.Statement {
+	return dbtest.Statement{
+		Query: "UPDATE outbox_events SET status = ?, attempts = ?, delivered_at = NOW(6) WHERE id = ?",
+		Args:  []driver.Value{outbox.StatusDelivered, int64(1), id},
+	}
+}
+
+func TestRelay(t *testing.T) {
+	t.Run("delivers the events in order within their topic", func(t *testing.T) {
+		sink := &testSink{}
+
+		execs, _ := relay(t, sink, 3, eventRow(1, "a", 0), eventRow(2, "b", 0), eventRow(3, "a", 0))
+
+		assert.Equal(t, []int64{1, 2, 3}, sink.delivered)
+		assert.Equal(t, []dbtest.Statement{deliveredExec(1), deliveredExec(2), deliveredExec(3)}, execs)
+	})
+
+	t.Run("holds back the next events of a topic waiting for a retry", func(t *testing.T) {
+		sink := &testSink{failing: map[int64]bool{1: true}}
+
+		execs, query := relay(t, sink, 2, eventRow(1, "a", 0), eventRow(2, "a", 0), eventRow(3, "b", 0), eventRow(4, "a", 0))
+
+		// the events held back by a previous retry are excluded from the batches
+		assert.Contains(t, query.Query, "NOT EXISTS ( SELECT 1 FROM outbox_events b WHERE b.topic = e.topic AND b.status = ? AND b.id < e.id")
+		assert.Equal(t, []driver.Value{outbox.StatusPending, outbox.StatusPending, int64(10)}, query.Args)
+
+		assert.Equal(t, []int64{1, 3}, sink.delivered)
+		assert.Equal(t, []dbtest.Statement{
+			{
+				Query: "UPDATE outbox_events SET attempts = ?, last_error = ?, next_attempt_at = NOW(6) + INTERVAL ? MICROSECOND WHERE id = ?",
+				Args:  []driver.Value{int64(1), "failure", time.Second.Microseconds(), int64(1)},
+			},
+			deliveredExec(3),
+		}, execs)
+	})
+
+	t.Run("retries the events with an exponential backoff", func(t *testing.T) {
+		sink := &testSink{failing: map[int64]bool{1: true}}
+
+		execs, _ := relay(t, sink, 1, eventRow(1, "a", 1))
+
+		assert.Equal(t, []dbtest.Statement{
+			{
+				Query: "UPDATE outbox_events SET attempts = ?, last_error = ?, next_attempt_at = NOW(6) + INTERVAL ? MICROSECOND WHERE id = ?",
+				Args:  []driver.Value{int64(2), "failure", (2 * time.Second).Microseconds(), int64(1)},
+			},
+		}, execs)
+	})
+
+	t.Run("moves the events failing on their last attempt to the dead state", func(t *testing.T) {
+		sink := &testSink{failing: map[int64]bool{1: true}}
+
+		execs, _ := relay(t, sink, 1, eventRow(1, "a", 2))
+
+		assert.Equal(t, []dbtest.Statement{
+			{
+				Query: "UPDATE outbox_events SET status = ?, attempts = ?, last_error = ? WHERE id = ?",
+				Args:  []driver.Value{outbox.StatusDead, int64(3), "failure", int64(1)},
+			},
+		}, execs)
+	})
+}
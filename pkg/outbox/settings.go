@@ -0,0 +1,23 @@
+package outbox
+
+import "time"
+
+// Settings configures the outbox table, and the relay: it polls up to batch size pending events every poll interval,
+// and retries their delivery with an exponential backoff until their max attempts, before moving them to the dead state.
+type Settings struct {
+	Table          string        `mapstructure:"table" default:"outbox_events" validate:"required"`
+	PollInterval   time.Duration `mapstructure:"poll_interval" default:"1s" validate:"gt=0"`
+	BatchSize      int           `mapstructure:"batch_size" default:"100" validate:"gt=0"`
+	MaxAttempts    int           `mapstructure:"max_attempts" default:"10" validate:"gt=0"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff" default:"1s" validate:"gt=0"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff" default:"5m" validate:"gt=0"`
+	Sink           SinkSettings  `mapstructure:"sink"`
+}
+
+// SinkSettings selects the sink delivering the events: log, webhook (posting the events to the url),
+// or the name of a sink registered with AsSink.
+type SinkSettings struct {
+	Type    string            `mapstructure:"type" default:"log" validate:"required"`
+	URL     string            `mapstructure:"url" validate:"required_if=Type webhook,omitempty,url"`
+	Headers map[string]string `mapstructure:"headers"`
+}
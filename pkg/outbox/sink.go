@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Event is an outbox event, delivered in its topic order (best-effort, see Relay) at least once: the sinks consumers
+// can deduplicate the events with their idempotency key.
+type Event struct {
+	ID             int64           `json:"id"`
+	Topic          string          `json:"topic"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Sink delivers the events, the delivery context carries the trace of the event publication.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event Event) error
+}
+
+var _ Sink = (*LogSink)(nil)
+
+// LogSink logs the events.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+func NewLogSink(logger *slog.Logger) *LogSink {
+	return &LogSink{
+		logger: logger,
+	}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Deliver(ctx context.Context, event Event) error {
+	s.logger.InfoContext(
+		ctx,
+		"outbox event",
+		"id", event.ID,
+		"topic", event.Topic,
+		"idempotency_key", event.IdempotencyKey,
+		"payload", string(event.Payload),
+	)
+
+	return nil
+}
+
+var _ Sink = (*WebhookSink)(nil)
+
+// WebhookSink posts the events as JSON to a URL, with their idempotency key in the Idempotency-Key header,
+// and expects a 2xx response.
+type WebhookSink struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func NewWebhookSink(client *http.Client, url string, headers map[string]string) *WebhookSink {
+	return &WebhookSink{
+		client:  client,
+		url:     url,
+		headers: headers,
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to prepare webhook request: %w", err)
+	}
+
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.IdempotencyKey)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer func() {
+		// drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected webhook response status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink(t *testing.T) {
+	event := outbox.Event{
+		ID:             1,
+		Topic:          "users.created",
+		IdempotencyKey: "key",
+		Payload:        json.RawMessage(`{"name":"alice"}`),
+	}
+
+	t.Run("delivers the event", func(t *testing.T) {
+		var received outbox.Event
+		var headers http.Header
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header
+
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &received)
+
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		sink := outbox.NewWebhookSink(server.Client(), server.URL, map[string]string{"Authorization": "Bearer token"})
+
+		require.NoError(t, sink.Deliver(context.Background(), event))
+
+		assert.Equal(t, "key", headers.Get("Idempotency-Key"))
+		assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+		assert.Equal(t, event.Topic, received.Topic)
+		assert.JSONEq(t, `{"name":"alice"}`, string(received.Payload))
+	})
+
+	t.Run("fails on non 2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		sink := outbox.NewWebhookSink(server.Client(), server.URL, nil)
+
+		assert.EqualError(t, sink.Deliver(context.Background(), event), "unexpected webhook response status 503")
+	})
+}
@@ -0,0 +1,321 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// lockMargin extends the jobs lock beyond the handler timeout, so a job is not reclaimed while its outcome is recorded.
+const lockMargin = 30 * time.Second
+
+type job struct {
+	id           int64
+	payload      []byte
+	attempts     int
+	maxAttempts  int
+	traceContext []byte
+}
+
+var _ worker.Worker = (*Consumer)(nil)
+
+// Consumer is a worker processing the jobs of a type with its handler.
+type Consumer struct {
+	db         *sql.DB
+	logger     *slog.Logger
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	metrics    *consumerMetrics
+	settings   Settings
+	handler    HandlerRegistration
+}
+
+func NewConsumer(
+	db *sql.DB,
+	logger *slog.Logger,
+	tracer trace.Tracer,
+	propagator propagation.TextMapPropagator,
+	meter metric.Meter,
+	settings Settings,
+	handler HandlerRegistration,
+) (*Consumer, error) {
+	metrics, err := newConsumerMetrics(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Consumer{
+		db:         db,
+		logger:     logger,
+		tracer:     tracer,
+		propagator: propagator,
+		metrics:    metrics,
+		settings:   settings,
+		handler:    handler,
+	}, nil
+}
+
+func (c *Consumer) Name() string {
+	return "queue-" + c.handler.Type
+}
+
+// Run processes the jobs with concurrency pollers, and waits for the running ones on cancellation,
+// or on a poller failure.
+func (c *Consumer) Run(ctx context.Context) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	for range c.settings.Concurrency {
+		group.Go(func() error {
+			return c.poll(groupCtx)
+		})
+	}
+
+	return group.Wait()
+}
+
+// poll processes the jobs one by one, and waits for the poll interval when none are available.
+func (c *Consumer) poll(ctx context.Context) error {
+	ticker := time.NewTicker(c.settings.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		j, err := c.claim(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if j != nil {
+			c.process(ctx, j)
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim locks the next due job, or a running job whose lock expired, without blocking on the jobs locked by other consumers.
+// The jobs whose lock expired after their max attempts, for example because their handler crashed the process, are moved
+// to the dead state instead.
+func (c *Consumer) claim(ctx context.Context) (*job, error) {
+	for {
+		j, dead, err := c.claimNext(ctx)
+		if !dead {
+			return j, err
+		}
+	}
+}
+
+// claimNext claims the next job, or moves it to the dead state and reports it as dead.
+func (c *Consumer) claimNext(ctx context.Context) (*job, bool, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin %s job claim: %w", c.handler.Type, err)
+	}
+	defer tx.Rollback()
+
+	j := &job{}
+
+	err = tx.QueryRowContext(
+		ctx,
+		fmt.Sprintf(
+			`SELECT id, payload, attempts, max_attempts, trace_context FROM %s
+			WHERE type = ? AND ((status = ? AND run_at <= NOW(6)) OR (status = ? AND locked_until < NOW(6)))
+			ORDER BY run_at, id LIMIT 1
+			FOR UPDATE SKIP LOCKED`,
+			c.settings.Table,
+		),
+		c.handler.Type,
+		StatusPending,
+		StatusRunning,
+	).Scan(&j.id, &j.payload, &j.attempts, &j.maxAttempts, &j.traceContext)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("failed to claim %s job: %w", c.handler.Type, err)
+	}
+
+	dead := j.attempts >= j.maxAttempts
+
+	if dead {
+		_, err = tx.ExecContext(
+			ctx,
+			fmt.Sprintf("UPDATE %s SET status = ?, last_error = ?, locked_until = NULL WHERE id = ?", c.settings.Table),
+			StatusDead,
+			fmt.Sprintf("job lock expired on its attempt %d", j.attempts),
+			j.id,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to move %s job %d to dead state: %w", c.handler.Type, j.id, err)
+		}
+
+		c.logger.ErrorContext(ctx, "job lock expired after its max attempts, moved to dead state", "type", c.handler.Type, "id", j.id, "attempts", j.attempts)
+	} else {
+		_, err = tx.ExecContext(
+			ctx,
+			fmt.Sprintf(
+				"UPDATE %s SET status = ?, attempts = ?, locked_until = NOW(6) + INTERVAL ? MICROSECOND WHERE id = ?",
+				c.settings.Table,
+			),
+			StatusRunning,
+			j.attempts+1,
+			(c.settings.Timeout + lockMargin).Microseconds(),
+			j.id,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to lock %s job %d: %w", c.handler.Type, j.id, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit %s job %d claim: %w", c.handler.Type, j.id, err)
+	}
+
+	if dead {
+		return nil, true, nil
+	}
+
+	j.attempts++
+
+	return j, false, nil
+}
+
+// process handles the job in the trace it was enqueued from, and records its outcome.
+func (c *Consumer) process(ctx context.Context, j *job) {
+	carrier := propagation.MapCarrier{}
+	if len(j.traceContext) > 0 {
+		if err := json.Unmarshal(j.traceContext, &carrier); err != nil {
+			c.logger.WarnContext(ctx, "failed to decode job trace context", "type", c.handler.Type, "id", j.id, "error", err)
+		}
+	}
+
+	ctx, span := c.tracer.Start(
+		c.propagator.Extract(ctx, carrier),
+		"process "+c.handler.Type,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(jobIDKey.Int64(j.id), jobTypeKey.String(c.handler.Type)),
+	)
+	defer span.End()
+
+	handleCtx, cancel := context.WithTimeout(ctx, c.settings.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.handle(handleCtx, j)
+	duration := time.Since(start)
+
+	// the job outcome is recorded even if the consumer is stopping
+	status, err := c.complete(context.WithoutCancel(ctx), j, err)
+
+	span.SetAttributes(jobStatusKey.String(status))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	c.metrics.duration.Record(
+		ctx,
+		duration.Seconds(),
+		metric.WithAttributes(jobTypeKey.String(c.handler.Type), jobStatusKey.String(status)),
+	)
+}
+
+// handle runs the handler, and turns its panics into errors with their stack trace.
+func (c *Consumer) handle(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s job handler panicked: %v\n%s", c.handler.Type, r, debug.Stack())
+		}
+	}()
+
+	return c.handler.Handle(ctx, j.payload)
+}
+
+// complete marks the job as done on success, or schedules its retry, or moves it to the dead state
+// after its max attempts, and returns its new status with the handling error.
+// The job is only updated if still locked by this attempt, its outcome is discarded if it was reclaimed meanwhile.
+func (c *Consumer) complete(ctx context.Context, j *job, handleErr error) (string, error) {
+	var (
+		status string
+		query  string
+		args   []any
+	)
+
+	switch {
+	case handleErr == nil:
+		status = StatusDone
+		query = "UPDATE %s SET status = ?, locked_until = NULL WHERE id = ?"
+		args = []any{status, j.id}
+
+		c.logger.DebugContext(ctx, "job processed", "type", c.handler.Type, "id", j.id, "attempts", j.attempts)
+	case j.attempts >= j.maxAttempts:
+		status = StatusDead
+		query = "UPDATE %s SET status = ?, last_error = ?, locked_until = NULL WHERE id = ?"
+		args = []any{status, handleErr.Error(), j.id}
+
+		c.logger.ErrorContext(ctx, "job failed, moved to dead state", "type", c.handler.Type, "id", j.id, "attempts", j.attempts, "error", handleErr)
+	default:
+		status = StatusPending
+		delay := c.backoff(j.attempts)
+		query = "UPDATE %s SET status = ?, last_error = ?, locked_until = NULL, run_at = NOW(6) + INTERVAL ? MICROSECOND WHERE id = ?"
+		args = []any{status, handleErr.Error(), delay.Microseconds(), j.id}
+
+		c.logger.WarnContext(ctx, "job failed, retrying", "type", c.handler.Type, "id", j.id, "attempts", j.attempts, "delay", delay, "error", handleErr)
+	}
+
+	res, err := c.db.ExecContext(
+		ctx,
+		fmt.Sprintf(query+" AND status = ? AND attempts = ?", c.settings.Table),
+		append(args, StatusRunning, j.attempts)...,
+	)
+	if err == nil {
+		var affected int64
+		if affected, err = res.RowsAffected(); err == nil && affected == 0 {
+			err = fmt.Errorf("%s job %d lock lost on its attempt %d, outcome discarded", c.handler.Type, j.id, j.attempts)
+		}
+	}
+
+	if err != nil {
+		c.logger.ErrorContext(ctx, "failed to complete job", "type", c.handler.Type, "id", j.id, "status", status, "error", err)
+
+		return status, errors.Join(handleErr, err)
+	}
+
+	return status, handleErr
+}
+
+// backoff returns the exponential delay before the retry of a job, capped to the max backoff.
+func (c *Consumer) backoff(attempts int) time.Duration {
+	delay := c.settings.InitialBackoff
+	for i := 1; i < attempts && delay < c.settings.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, c.settings.MaxBackoff)
+}
@@ -0,0 +1,200 @@
+package queue_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var testSettings = queue.Settings{
+	Table:          "queue_jobs",
+	PollInterval:   time.Millisecond,
+	Concurrency:    1,
+	Timeout:        time.Second,
+	MaxAttempts:    10,
+	InitialBackoff: time.Second,
+	MaxBackoff:     5 * time.Second,
+}
+
+type testConsumer struct {
+	*queue.Consumer
+	connector *dbtest.Connector
+	recorder  *tracetest.SpanRecorder
+	handled   chan []byte
+}
+
+func newConsumer(t *testing.T, connector *dbtest.Connector, handleErr error) testConsumer {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	handled := make(chan []byte, 10)
+
+	consumer, err := queue.NewConsumer(
+		sql.OpenDB(connector),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test"),
+		propagation.TraceContext{},
+		noop.NewMeterProvider().Meter("test"),
+		testSettings,
+		queue.HandlerRegistration{
+			Type: "test",
+			Handle: func(_ context.Context, payload []byte) error {
+				handled <- payload
+
+				return handleErr
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	return testConsumer{Consumer: consumer, connector: connector, recorder: recorder, handled: handled}
+}
+
+// run runs the consumer until it recorded the statements count.
+func (c testConsumer) run(t *testing.T, statements int) []dbtest.Statement {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(c.connector.Execs()) >= statements
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	return c.connector.Execs()
+}
+
+// jobRow returns a claimable job row, with its attempts so far.
+func jobRow(attempts int64, maxAttempts int64, traceContext []byte) []driver.Value {
+	return []driver.Value{int64(1), []byte(`{"name":"alice"}`), attempts, maxAttempts, traceContext}
+}
+
+// claimable returns the job row to the next claim query.
+func claimable(row []driver.Value) dbtest.ConnectorOption {
+	return dbtest.WithRows("SELECT id, payload", []string{"id", "payload", "attempts", "max_attempts", "trace_context"}, row)
+}
+
+func lockExec(attempts int64) dbtest.Statement {
+	return dbtest.Statement{
+		Query: "UPDATE queue_jobs SET status = ?, attempts = ?, locked_until = NOW(6) + INTERVAL ? MICROSECOND WHERE id = ?",
+		Args:  []driver.Value{queue.StatusRunning, attempts, (31 * time.Second).Microseconds(), int64(1)},
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	t.Run("processes the jobs in their enqueuing trace", func(t *testing.T) {
+		connector := dbtest.NewConnector()
+		recorder := tracetest.NewSpanRecorder()
+		tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+		ctx, span := tracer.Start(context.Background(), "request")
+		_, err := queue.NewQueue(sql.OpenDB(connector), tracer, propagation.TraceContext{}, testSettings).
+			Enqueue(ctx, "test", map[string]string{"name": "alice"})
+		require.NoError(t, err)
+		span.End()
+
+		enqueued := connector.Execs()[0]
+
+		consumer := newConsumer(t, dbtest.NewConnector(claimable(jobRow(0, 5, enqueued.Args[4].([]byte)))), nil)
+		execs := consumer.run(t, 2)
+
+		assert.Equal(t, `{"name":"alice"}`, string(<-consumer.handled))
+		assert.Equal(t, []dbtest.Statement{
+			lockExec(1),
+			{
+				Query: "UPDATE queue_jobs SET status = ?, locked_until = NULL WHERE id = ? AND status = ? AND attempts = ?",
+				Args:  []driver.Value{queue.StatusDone, int64(1), queue.StatusRunning, int64(1)},
+			},
+		}, execs)
+
+		spans := consumer.recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "process test", spans[0].Name())
+		assert.Equal(t, trace.SpanKindConsumer, spans[0].SpanKind())
+		assert.Equal(t, span.SpanContext().TraceID(), spans[0].Parent().TraceID())
+
+		// the enqueuing span, child of the request span
+		enqueuing := recorder.Ended()[0]
+		assert.Equal(t, "enqueue test", enqueuing.Name())
+		assert.Equal(t, enqueuing.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	})
+
+	t.Run("retries the failed jobs with an exponential backoff", func(t *testing.T) {
+		for attempts, delay := range map[int64]time.Duration{
+			0: time.Second,
+			1: 2 * time.Second,
+			2: 4 * time.Second,
+			3: 5 * time.Second,
+		} {
+			consumer := newConsumer(t, dbtest.NewConnector(claimable(jobRow(attempts, 10, nil))), errors.New("failure"))
+			execs := consumer.run(t, 2)
+
+			assert.Equal(t, dbtest.Statement{
+				Query: "UPDATE queue_jobs SET status = ?, last_error = ?, locked_until = NULL, run_at = NOW(6) + INTERVAL ? MICROSECOND " +
+					"WHERE id = ? AND status = ? AND attempts = ?",
+				Args: []driver.Value{queue.StatusPending, "failure", delay.Microseconds(), int64(1), queue.StatusRunning, attempts + 1},
+			}, execs[1])
+		}
+	})
+
+	t.Run("moves the jobs failing on their last attempt to the dead state", func(t *testing.T) {
+		consumer := newConsumer(t, dbtest.NewConnector(claimable(jobRow(4, 5, nil))), errors.New("failure"))
+		execs := consumer.run(t, 2)
+
+		assert.Equal(t, []dbtest.Statement{
+			lockExec(5),
+			{
+				Query: "UPDATE queue_jobs SET status = ?, last_error = ?, locked_until = NULL WHERE id = ? AND status = ? AND attempts = ?",
+				Args:  []driver.Value{queue.StatusDead, "failure", int64(1), queue.StatusRunning, int64(5)},
+			},
+		}, execs)
+
+		spans := consumer.recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	})
+
+	t.Run("moves the jobs whose lock expired on their last attempt to the dead state", func(t *testing.T) {
+		consumer := newConsumer(t, dbtest.NewConnector(claimable(jobRow(5, 5, nil))), nil)
+		execs := consumer.run(t, 1)
+
+		assert.Equal(t, []dbtest.Statement{
+			{
+				Query: "UPDATE queue_jobs SET status = ?, last_error = ?, locked_until = NULL WHERE id = ?",
+				Args:  []driver.Value{queue.StatusDead, "job lock expired on its attempt 5", int64(1)},
+			},
+		}, execs)
+		assert.Empty(t, consumer.handled)
+	})
+
+	t.Run("discards the outcome of the reclaimed jobs", func(t *testing.T) {
+		consumer := newConsumer(t, dbtest.NewConnector(dbtest.WithAffected(0), claimable(jobRow(0, 5, nil))), nil)
+		consumer.run(t, 2)
+
+		require.Len(t, consumer.recorder.Ended(), 1)
+		assert.Equal(t, "test job 1 lock lost on its attempt 1, outcome discarded", consumer.recorder.Ended()[0].Status().Description)
+	})
+}
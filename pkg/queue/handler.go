@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Handler handles the jobs of a type, with their payload decoded from JSON.
+type Handler[T any] interface {
+	Handle(ctx context.Context, payload T) error
+}
+
+// HandlerFunc is a function implementing Handler.
+type HandlerFunc[T any] func(ctx context.Context, payload T) error
+
+func (f HandlerFunc[T]) Handle(ctx context.Context, payload T) error {
+	return f(ctx, payload)
+}
+
+// HandlerRegistration is a Handler registered for a jobs type, with its payload type erased.
+type HandlerRegistration struct {
+	Type   string
+	Handle func(ctx context.Context, payload []byte) error
+}
+
+// NewHandlerRegistration registers the handler for the jobs type.
+func NewHandlerRegistration[T any](jobType string, handler Handler[T]) HandlerRegistration {
+	return HandlerRegistration{
+		Type: jobType,
+		Handle: func(ctx context.Context, payload []byte) error {
+			var decoded T
+			if err := json.Unmarshal(payload, &decoded); err != nil {
+				return fmt.Errorf("failed to decode %s job payload: %w", jobType, err)
+			}
+
+			return handler.Handle(ctx, decoded)
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type testPayload struct {
+	Name string `json:"name"`
+}
+
+type testHandler struct {
+	handled []testPayload
+}
+
+func (h *testHandler) Handle(_ context.Context, payload testPayload) error {
+	h.handled = append(h.handled, payload)
+
+	return nil
+}
+
+func TestAsHandler(t *testing.T) {
+	handler := &testHandler{}
+
+	var registrations []queue.HandlerRegistration
+
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fx.Supply(handler),
+		queue.AsHandler[testPayload]("test", func(h *testHandler) *testHandler {
+			return h
+		}),
+		fx.Invoke(fx.Annotate(
+			func(r []queue.HandlerRegistration) {
+				registrations = r
+			},
+			fx.ParamTags(`group:"queue-handlers"`),
+		)),
+	)
+	app.RequireStart().RequireStop()
+
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "test", registrations[0].Type)
+
+	ctx := context.Background()
+
+	require.NoError(t, registrations[0].Handle(ctx, []byte(`{"name":"alice"}`)))
+	assert.Equal(t, []testPayload{{Name: "alice"}}, handler.handled)
+
+	err := registrations[0].Handle(ctx, []byte(`invalid`))
+	assert.ErrorContains(t, err, "failed to decode test job payload")
+}
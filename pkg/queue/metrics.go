@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	jobIDKey     = attribute.Key("queue.job.id")
+	jobTypeKey   = attribute.Key("queue.job.type")
+	jobStatusKey = attribute.Key("queue.job.status")
+)
+
+type consumerMetrics struct {
+	duration metric.Float64Histogram
+}
+
+func newConsumerMetrics(meter metric.Meter) (*consumerMetrics, error) {
+	duration, err := meter.Float64Histogram(
+		"queue.job.duration",
+		metric.WithDescription("Duration of the jobs processing, by resulting job status."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consumerMetrics{
+		duration: duration,
+	}, nil
+}
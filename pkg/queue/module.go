@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"database/sql"
+	"log/slog"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+const ModuleName = "queue"
+
+var Module = fx.Module(
+	ModuleName,
+	config.AsSchema[Settings](ModuleName),
+	fx.Provide(
+		ProvideQueue,
+	),
+	worker.AsWorkerRegistrations(ProvideConsumers),
+)
+
+type ProvideQueueParams struct {
+	fx.In
+	Config         *config.Config
+	DB             *sql.DB
+	Propagator     propagation.TextMapPropagator
+	TracerProvider trace.TracerProvider
+}
+
+func ProvideQueue(params ProvideQueueParams) (*Queue, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewQueue(
+		params.DB,
+		params.TracerProvider.Tracer("github.com/go-oryn/oryn-sandbox/pkg/queue"),
+		params.Propagator,
+		settings,
+	), nil
+}
+
+type ProvideConsumersParams struct {
+	fx.In
+	Config         *config.Config
+	DB             *sql.DB
+	Logger         *slog.Logger
+	Propagator     propagation.TextMapPropagator
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Handlers       []HandlerRegistration `group:"queue-handlers"`
+}
+
+// ProvideConsumers provides a consumer worker per handler.
+func ProvideConsumers(params ProvideConsumersParams) ([]worker.WorkerRegistration, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations := make([]worker.WorkerRegistration, 0, len(params.Handlers))
+	for _, handler := range params.Handlers {
+		consumer, err := NewConsumer(
+			params.DB,
+			params.Logger,
+			params.TracerProvider.Tracer("github.com/go-oryn/oryn-sandbox/pkg/queue"),
+			params.Propagator,
+			params.MeterProvider.Meter("github.com/go-oryn/oryn-sandbox/pkg/queue"),
+			settings,
+			handler,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations = append(registrations, worker.WorkerRegistration{Worker: consumer})
+	}
+
+	return registrations, nil
+}
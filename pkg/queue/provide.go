@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"fmt"
+
+	"go.uber.org/fx"
+)
+
+// AsHandler registers a constructor of a Handler[T] for the jobs type.
+func AsHandler[T any](jobType string, constructor any) fx.Option {
+	name := fmt.Sprintf(`name:"queue-handler-%s"`, jobType)
+
+	return fx.Options(
+		fx.Provide(
+			fx.Annotate(
+				constructor,
+				fx.As(new(Handler[T])),
+				fx.ResultTags(name),
+			),
+		),
+		fx.Provide(
+			fx.Annotate(
+				func(handler Handler[T]) HandlerRegistration {
+					return NewHandlerRegistration(jobType, handler)
+				},
+				fx.ParamTags(name),
+				fx.ResultTags(`group:"queue-handlers"`),
+			),
+		),
+	)
+}
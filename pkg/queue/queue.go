@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusDead    = "dead"
+)
+
+type EnqueueOption func(o *enqueueOptions)
+
+type enqueueOptions struct {
+	delay       time.Duration
+	maxAttempts int
+}
+
+// WithDelay delays the job processing.
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.delay = delay
+	}
+}
+
+// WithMaxAttempts overrides the configured max attempts of the job, at least 1 or the job is not enqueued.
+func WithMaxAttempts(maxAttempts int) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.maxAttempts = maxAttempts
+	}
+}
+
+// Queue enqueues the jobs in the jobs table, with the trace context of the enqueuing span.
+type Queue struct {
+	db         *sql.DB
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	settings   Settings
+}
+
+func NewQueue(db *sql.DB, tracer trace.Tracer, propagator propagation.TextMapPropagator, settings Settings) *Queue {
+	return &Queue{
+		db:         db,
+		tracer:     tracer,
+		propagator: propagator,
+		settings:   settings,
+	}
+}
+
+// Enqueue enqueues a job of the type, with its payload encoded to JSON, and returns its id.
+// It joins the transaction of the context, see db.Transactor.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, options ...EnqueueOption) (int64, error) {
+	opts := enqueueOptions{
+		maxAttempts: q.settings.MaxAttempts,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	ctx, span := q.tracer.Start(ctx, "enqueue "+jobType, trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	id, err := q.enqueue(ctx, jobType, payload, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return 0, err
+	}
+
+	span.SetAttributes(jobIDKey.Int64(id), jobTypeKey.String(jobType))
+
+	return id, nil
+}
+
+func (q *Queue) enqueue(ctx context.Context, jobType string, payload any, opts enqueueOptions) (int64, error) {
+	// a job without any attempt would be dead-lettered before its first run
+	if opts.maxAttempts < 1 {
+		return 0, fmt.Errorf("invalid %s job max attempts %d, must be at least 1", jobType, opts.maxAttempts)
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode %s job payload: %w", jobType, err)
+	}
+
+	carrier := propagation.MapCarrier{}
+	q.propagator.Inject(ctx, carrier)
+
+	traceContext, err := json.Marshal(carrier)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode %s job trace context: %w", jobType, err)
+	}
+
+	res, err := db.QuerierFromContext(ctx, q.db).ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO %s (type, payload, status, max_attempts, trace_context, run_at)
+			VALUES (?, ?, ?, ?, ?, NOW(6) + INTERVAL ? MICROSECOND)`,
+			q.settings.Table,
+		),
+		jobType,
+		encodedPayload,
+		StatusPending,
+		opts.maxAttempts,
+		traceContext,
+		opts.delay.Microseconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue %s job: %w", jobType, err)
+	}
+
+	return res.LastInsertId()
+}
@@ -0,0 +1,30 @@
+package queue_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/db/dbtest"
+	"github.com/go-oryn/oryn-sandbox/pkg/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestQueueMaxAttempts(t *testing.T) {
+	connector := dbtest.NewConnector()
+	q := queue.NewQueue(sql.OpenDB(connector), tracenoop.NewTracerProvider().Tracer("test"), propagation.TraceContext{}, testSettings)
+
+	for _, maxAttempts := range []int{0, -1} {
+		_, err := q.Enqueue(context.Background(), "test", nil, queue.WithMaxAttempts(maxAttempts))
+		assert.ErrorContains(t, err, "invalid test job max attempts")
+	}
+
+	assert.Empty(t, connector.Execs())
+
+	_, err := q.Enqueue(context.Background(), "test", nil, queue.WithMaxAttempts(1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), connector.Execs()[0].Args[3])
+}
@@ -0,0 +1,16 @@
+package queue
+
+import "time"
+
+// Settings configures the jobs table, and the consumers: they poll the jobs every poll interval,
+// process up to concurrency jobs at once, each within the timeout, and retry the failed jobs with
+// an exponential backoff until their max attempts, before moving them to the dead state.
+type Settings struct {
+	Table          string        `mapstructure:"table" default:"queue_jobs" validate:"required"`
+	PollInterval   time.Duration `mapstructure:"poll_interval" default:"1s" validate:"gt=0"`
+	Concurrency    int           `mapstructure:"concurrency" default:"1" validate:"gt=0"`
+	Timeout        time.Duration `mapstructure:"timeout" default:"5m" validate:"gt=0"`
+	MaxAttempts    int           `mapstructure:"max_attempts" default:"5" validate:"gt=0"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff" default:"1s" validate:"gt=0"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff" default:"5m" validate:"gt=0"`
+}
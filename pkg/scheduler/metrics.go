@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	jobKey    = attribute.Key("scheduler.job")
+	statusKey = attribute.Key("scheduler.job.status")
+)
+
+type schedulerMetrics struct {
+	duration metric.Float64Histogram
+	skipped  metric.Int64Counter
+}
+
+func newSchedulerMetrics(meter metric.Meter) (*schedulerMetrics, error) {
+	duration, err := meter.Float64Histogram(
+		"scheduler.job.duration",
+		metric.WithDescription("Duration of the scheduled jobs runs."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped, err := meter.Int64Counter(
+		"scheduler.job.skipped",
+		metric.WithDescription("Number of scheduled jobs runs skipped while their previous run was still running."),
+		metric.WithUnit("{run}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedulerMetrics{
+		duration: duration,
+		skipped:  skipped,
+	}, nil
+}
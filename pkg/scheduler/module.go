@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+const ModuleName = "scheduler"
+
+var Module = fx.Module(
+	ModuleName,
+	config.AsSchema[Settings](ModuleName),
+	fx.Provide(
+		ProvideScheduler,
+	),
+	worker.AsWorkerRegistrations(ProvideJobWorkers),
+)
+
+type ProvideSchedulerParams struct {
+	fx.In
+	Logger         *slog.Logger
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+}
+
+func ProvideScheduler(params ProvideSchedulerParams) (*Scheduler, error) {
+	return NewScheduler(
+		params.Logger,
+		params.TracerProvider.Tracer("github.com/go-oryn/oryn-sandbox/pkg/scheduler"),
+		params.MeterProvider.Meter("github.com/go-oryn/oryn-sandbox/pkg/scheduler"),
+	)
+}
+
+type ProvideJobWorkersParams struct {
+	fx.In
+	Config    *config.Config
+	Scheduler *Scheduler
+	Jobs      []Job `group:"scheduler-jobs"`
+}
+
+// ProvideJobWorkers provides a worker per job, each job requires its schedule config.
+func ProvideJobWorkers(params ProvideJobWorkersParams) ([]worker.WorkerRegistration, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations := make([]worker.WorkerRegistration, 0, len(params.Jobs))
+	for _, job := range params.Jobs {
+		jobSettings, ok := settings.Jobs[job.Name()]
+		if !ok {
+			return nil, fmt.Errorf("missing %s.jobs.%s config for job %s", ModuleName, job.Name(), job.Name())
+		}
+
+		w, err := params.Scheduler.Worker(job, jobSettings)
+		if err != nil {
+			return nil, err
+		}
+
+		var options []worker.WorkerOption
+		if jobSettings.Singleton {
+			options = append(options, worker.Singleton())
+		}
+
+		registrations = append(registrations, worker.WorkerRegistration{
+			Worker:  w,
+			Options: options,
+		})
+	}
+
+	return registrations, nil
+}
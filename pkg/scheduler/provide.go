@@ -0,0 +1,14 @@
+package scheduler
+
+import "go.uber.org/fx"
+
+// AsJob registers a job constructor, the job schedule is configured under scheduler.jobs.<job name>.
+func AsJob(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(Job)),
+			fx.ResultTags(`group:"scheduler-jobs"`),
+		),
+	)
+}
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// parseSchedule returns the interval schedule if set, or the cron schedule otherwise.
+func parseSchedule(settings JobSettings) (cron.Schedule, error) {
+	if settings.Interval > 0 {
+		return intervalSchedule(settings.Interval), nil
+	}
+
+	schedule, err := cron.ParseStandard(settings.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule %q: %w", settings.Schedule, err)
+	}
+
+	return schedule, nil
+}
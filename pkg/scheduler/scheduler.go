@@ -0,0 +1,203 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Scheduler creates the workers running the jobs on their schedule.
+type Scheduler struct {
+	logger  *slog.Logger
+	tracer  trace.Tracer
+	metrics *schedulerMetrics
+}
+
+func NewScheduler(logger *slog.Logger, tracer trace.Tracer, meter metric.Meter) (*Scheduler, error) {
+	metrics, err := newSchedulerMetrics(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		logger:  logger,
+		tracer:  tracer,
+		metrics: metrics,
+	}, nil
+}
+
+// Worker returns a worker running the job with the settings, named after the job.
+func (s *Scheduler) Worker(job Job, settings JobSettings) (*JobWorker, error) {
+	schedule, err := parseSchedule(settings)
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", job.Name(), err)
+	}
+
+	if settings.Overlap == "" {
+		settings.Overlap = OverlapSkip
+	}
+
+	return &JobWorker{
+		scheduler: s,
+		job:       job,
+		settings:  settings,
+		schedule:  schedule,
+	}, nil
+}
+
+var _ worker.Worker = (*JobWorker)(nil)
+
+// JobWorker is a worker running a job on its schedule, until its context is canceled.
+type JobWorker struct {
+	scheduler *Scheduler
+	job       Job
+	settings  JobSettings
+	schedule  cron.Schedule
+}
+
+func (w *JobWorker) Name() string {
+	return w.job.Name()
+}
+
+// Run schedules the job runs, and waits for the running ones on cancellation.
+func (w *JobWorker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// with the skip policy, a trigger is skipped while the previous run is running
+	var running atomic.Bool
+
+	// with the queue policy, a sequential runner receives the triggers, one being queued while running
+	var triggers chan struct{}
+	if w.settings.Overlap == OverlapQueue {
+		triggers = make(chan struct{}, 1)
+
+		wg.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-triggers:
+					w.run(ctx)
+				}
+			}
+		})
+	}
+
+	for {
+		delay := time.Until(w.schedule.Next(time.Now()))
+		if w.settings.Jitter > 0 {
+			delay += rand.N(w.settings.Jitter)
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		switch w.settings.Overlap {
+		case OverlapSkip:
+			if !running.CompareAndSwap(false, true) {
+				w.skip(ctx)
+
+				continue
+			}
+
+			wg.Go(func() {
+				defer running.Store(false)
+
+				w.run(ctx)
+			})
+		case OverlapQueue:
+			select {
+			case triggers <- struct{}{}:
+			default:
+				w.skip(ctx)
+			}
+		default:
+			wg.Go(func() {
+				w.run(ctx)
+			})
+		}
+	}
+}
+
+// skip records a trigger skipped because of the previous runs.
+func (w *JobWorker) skip(ctx context.Context) {
+	w.scheduler.metrics.skipped.Add(ctx, 1, metric.WithAttributes(jobKey.String(w.job.Name())))
+	w.scheduler.logger.WarnContext(ctx, "skipped scheduled job run, previous run still running", "job", w.job.Name())
+}
+
+// run runs the job once, in a new trace.
+func (w *JobWorker) run(ctx context.Context) {
+	name := w.job.Name()
+
+	ctx, span := w.scheduler.tracer.Start(
+		ctx,
+		"job "+name,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(jobKey.String(name)),
+	)
+	defer span.End()
+
+	if w.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.settings.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := w.safeRun(ctx)
+	duration := time.Since(start)
+
+	status := "success"
+	if err != nil {
+		status = "failure"
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		w.scheduler.logger.ErrorContext(ctx, "scheduled job run failed", "job", name, "error", err, "duration", duration)
+	} else {
+		w.scheduler.logger.DebugContext(ctx, "scheduled job run succeeded", "job", name, "duration", duration)
+	}
+
+	w.scheduler.metrics.duration.Record(
+		ctx,
+		duration.Seconds(),
+		metric.WithAttributes(jobKey.String(name), statusKey.String(status)),
+	)
+}
+
+// safeRun runs the job, and turns its panics into errors with their stack trace.
+func (w *JobWorker) safeRun(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job %s panicked: %v\n%s", w.job.Name(), r, debug.Stack())
+		}
+	}()
+
+	return w.job.Run(ctx)
+}
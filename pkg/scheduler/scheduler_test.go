@@ -0,0 +1,148 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testJob struct {
+	runs    atomic.Int32
+	running atomic.Int32
+	maxRuns atomic.Int32
+	delay   time.Duration
+	err     error
+}
+
+func (j *testJob) Name() string {
+	return "test"
+}
+
+func (j *testJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+
+	running := j.running.Add(1)
+	defer j.running.Add(-1)
+
+	if running > j.maxRuns.Load() {
+		j.maxRuns.Store(running)
+	}
+
+	select {
+	case <-time.After(j.delay):
+		return j.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type testScheduler struct {
+	*scheduler.Scheduler
+	reader   *sdkmetric.ManualReader
+	recorder *tracetest.SpanRecorder
+}
+
+func newScheduler(t *testing.T) testScheduler {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	recorder := tracetest.NewSpanRecorder()
+
+	s, err := scheduler.NewScheduler(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test"),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+	)
+	require.NoError(t, err)
+
+	return testScheduler{Scheduler: s, reader: reader, recorder: recorder}
+}
+
+// run runs the job worker for the duration.
+func run(t *testing.T, s testScheduler, job scheduler.Job, settings scheduler.JobSettings, duration time.Duration) {
+	t.Helper()
+
+	w, err := s.Worker(job, settings)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	require.NoError(t, w.Run(ctx))
+}
+
+func TestJobWorkerOverlap(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		job := &testJob{delay: 35 * time.Millisecond}
+		run(t, newScheduler(t), job, scheduler.JobSettings{Interval: 10 * time.Millisecond}, 100*time.Millisecond)
+
+		assert.Equal(t, int32(1), job.maxRuns.Load())
+		assert.Less(t, job.runs.Load(), int32(5))
+	})
+
+	t.Run("skip without overlap", func(t *testing.T) {
+		job := &testJob{}
+		run(t, newScheduler(t), job, scheduler.JobSettings{Interval: 10 * time.Millisecond}, 100*time.Millisecond)
+
+		assert.GreaterOrEqual(t, job.runs.Load(), int32(7))
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		job := &testJob{delay: 15 * time.Millisecond}
+		run(t, newScheduler(t), job, scheduler.JobSettings{Interval: 10 * time.Millisecond, Overlap: scheduler.OverlapQueue}, 100*time.Millisecond)
+
+		assert.Equal(t, int32(1), job.maxRuns.Load())
+		assert.GreaterOrEqual(t, job.runs.Load(), int32(4))
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		job := &testJob{delay: 35 * time.Millisecond}
+		run(t, newScheduler(t), job, scheduler.JobSettings{Interval: 10 * time.Millisecond, Overlap: scheduler.OverlapAllow}, 100*time.Millisecond)
+
+		assert.Greater(t, job.maxRuns.Load(), int32(1))
+	})
+}
+
+func TestJobWorkerTelemetry(t *testing.T) {
+	s := newScheduler(t)
+
+	job := &testJob{delay: time.Second, err: errors.New("job error")}
+	run(t, s, job, scheduler.JobSettings{Interval: 10 * time.Millisecond, Timeout: 5 * time.Millisecond}, 30*time.Millisecond)
+
+	spans := s.recorder.Ended()
+	require.NotEmpty(t, spans)
+	assert.Equal(t, "job test", spans[0].Name())
+	assert.False(t, spans[0].Parent().IsValid())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), spans[0].Status().Description)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, s.reader.Collect(context.Background(), &rm))
+
+	duration := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	assert.Equal(t, uint64(len(spans)), duration.DataPoints[0].Count)
+}
+
+func TestSchedulerWorker(t *testing.T) {
+	s := newScheduler(t)
+
+	w, err := s.Worker(&testJob{}, scheduler.JobSettings{Schedule: "*/5 * * * *"})
+	require.NoError(t, err)
+	assert.Equal(t, "test", w.Name())
+
+	_, err = s.Worker(&testJob{}, scheduler.JobSettings{Schedule: "invalid"})
+	assert.ErrorContains(t, err, `job test: invalid cron schedule "invalid"`)
+}
@@ -0,0 +1,30 @@
+package scheduler
+
+import "time"
+
+const (
+	OverlapSkip  = "skip"
+	OverlapQueue = "queue"
+	OverlapAllow = "allow"
+)
+
+// Settings configures the scheduled jobs, by job name.
+type Settings struct {
+	Jobs map[string]JobSettings `mapstructure:"jobs" validate:"dive"`
+}
+
+// JobSettings configures when a job runs, with a cron expression (like */5 * * * * or @hourly) or an interval,
+// delayed by a random jitter. The overlap policy applies when a run is due while the previous one is still running:
+//   - skip (default): the run is skipped
+//   - queue: the run starts once the previous one ends, with at most one queued run
+//   - allow: the runs are concurrent
+//
+// A singleton job runs on a single replica, see worker.SingletonWorker.
+type JobSettings struct {
+	Schedule  string        `mapstructure:"schedule" validate:"required_without=Interval,excluded_with=Interval"`
+	Interval  time.Duration `mapstructure:"interval" validate:"gte=0"`
+	Jitter    time.Duration `mapstructure:"jitter" validate:"gte=0"`
+	Overlap   string        `mapstructure:"overlap" validate:"omitempty,oneof=skip queue allow"`
+	Timeout   time.Duration `mapstructure:"timeout" validate:"gte=0"`
+	Singleton bool          `mapstructure:"singleton"`
+}
@@ -2,19 +2,18 @@ package worker
 
 import (
 	"context"
-	"errors"
-	"log/slog"
+	"fmt"
+	"strings"
 )
 
+// WorkersProbe fails when critical workers failed, and reports each worker status in its details.
 type WorkersProbe struct {
-	logger *slog.Logger
-	pool   *Pool
+	pool *Pool
 }
 
-func NewWorkersProbe(logger *slog.Logger, pool *Pool) *WorkersProbe {
+func NewWorkersProbe(pool *Pool) *WorkersProbe {
 	return &WorkersProbe{
-		logger: logger,
-		pool:   pool,
+		pool: pool,
 	}
 }
 
@@ -22,14 +21,22 @@ func (p *WorkersProbe) Name() string {
 	return "workers"
 }
 
-func (p *WorkersProbe) Probe(ctx context.Context) error {
-	if !p.pool.Running() {
-		msg := "worker pool is not running"
+func (p *WorkersProbe) Probe(context.Context) error {
+	var failed []string
 
-		p.logger.ErrorContext(ctx, msg)
+	for _, status := range p.pool.Status() {
+		if status.Critical && status.State == WorkerFailed {
+			failed = append(failed, status.Name)
+		}
+	}
 
-		return errors.New(msg)
+	if len(failed) > 0 {
+		return fmt.Errorf("critical workers failed: %s", strings.Join(failed, ", "))
 	}
 
 	return nil
 }
+
+func (p *WorkersProbe) Details() any {
+	return p.pool.Status()
+}
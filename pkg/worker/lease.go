@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Lease is a named lease, held by a single holder until it expires.
+type Lease struct {
+	Name      string
+	Holder    string
+	ExpiresAt time.Time
+}
+
+// LeaseStore acquires the leases.
+type LeaseStore interface {
+	// Acquire acquires the lease for the holder for the ttl, if free, expired or already held by the holder,
+	// and returns the lease with its current holder.
+	Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (Lease, error)
+	// Release releases the lease if held by the holder.
+	Release(ctx context.Context, name string, holder string) error
+}
+
+var _ LeaseStore = (*SQLLeaseStore)(nil)
+
+// SQLLeaseStore stores the leases in a MySQL table, relying on the db clock for their expiration.
+type SQLLeaseStore struct {
+	db    *sql.DB
+	table string
+}
+
+func NewSQLLeaseStore(db *sql.DB, table string) *SQLLeaseStore {
+	return &SQLLeaseStore{
+		db:    db,
+		table: table,
+	}
+}
+
+func (s *SQLLeaseStore) Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (Lease, error) {
+	// the holder assignment is evaluated first, the expiration is then extended only if the holder is the new one
+	_, err := s.db.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`INSERT INTO %s (name, holder, expires_at) VALUES (?, ?, NOW(6) + INTERVAL ? MICROSECOND)
+			ON DUPLICATE KEY UPDATE
+				holder = IF(holder = VALUES(holder) OR expires_at < NOW(6), VALUES(holder), holder),
+				expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at)`,
+			s.table,
+		),
+		name,
+		holder,
+		ttl.Microseconds(),
+	)
+	if err != nil {
+		return Lease{}, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+
+	lease := Lease{Name: name}
+
+	var remaining int64
+
+	err = s.db.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT holder, TIMESTAMPDIFF(MICROSECOND, NOW(6), expires_at) FROM %s WHERE name = ?", s.table),
+		name,
+	).Scan(&lease.Holder, &remaining)
+	if err != nil {
+		return Lease{}, fmt.Errorf("failed to read lease %s: %w", name, err)
+	}
+
+	lease.ExpiresAt = time.Now().Add(time.Duration(remaining) * time.Microsecond)
+
+	return lease, nil
+}
+
+func (s *SQLLeaseStore) Release(ctx context.Context, name string, holder string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE name = ? AND holder = ?", s.table),
+		name,
+		holder,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	workerKey = attribute.Key("worker.name")
+	stateKey  = attribute.Key("worker.state")
+	holderKey = attribute.Key("worker.lease.holder")
+)
+
+type poolMetrics struct {
+	state    metric.Int64ObservableGauge
+	restarts metric.Int64ObservableCounter
+	uptime   metric.Float64ObservableGauge
+	leases   metric.Int64ObservableGauge
+}
+
+func newPoolMetrics(meter metric.Meter) (*poolMetrics, error) {
+	state, err := meter.Int64ObservableGauge(
+		"worker.state",
+		metric.WithDescription("State of the workers: 1 for their current state, 0 for the others."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	restarts, err := meter.Int64ObservableCounter(
+		"worker.restarts",
+		metric.WithDescription("Number of restarts of the workers."),
+		metric.WithUnit("{restart}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	uptime, err := meter.Float64ObservableGauge(
+		"worker.uptime",
+		metric.WithDescription("Duration of the workers current run."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := meter.Int64ObservableGauge(
+		"worker.lease.held",
+		metric.WithDescription("Lease of the singleton workers: 1 if held by this replica, 0 otherwise."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poolMetrics{
+		state:    state,
+		restarts: restarts,
+		uptime:   uptime,
+		leases:   leases,
+	}, nil
+}
+
+// observe reports the workers statuses.
+func (p *Pool) observe(_ context.Context, observer metric.Observer) error {
+	for _, status := range p.Status() {
+		name := workerKey.String(status.Name)
+
+		for _, state := range WorkerStates {
+			var value int64
+			if state == status.State {
+				value = 1
+			}
+
+			observer.ObserveInt64(p.metrics.state, value, metric.WithAttributes(name, stateKey.String(string(state))))
+		}
+
+		observer.ObserveInt64(p.metrics.restarts, int64(status.Restarts), metric.WithAttributes(name))
+		observer.ObserveFloat64(p.metrics.uptime, status.Uptime.Seconds(), metric.WithAttributes(name))
+
+		if status.Lease != nil {
+			var held int64
+			if status.Lease.Held {
+				held = 1
+			}
+
+			observer.ObserveInt64(p.metrics.leases, held, metric.WithAttributes(name, holderKey.String(status.Lease.Holder)))
+		}
+	}
+
+	return nil
+}
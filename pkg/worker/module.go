@@ -2,8 +2,15 @@ package worker
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
 
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/lifecycle"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/fx"
 )
 
@@ -11,32 +18,98 @@ const ModuleName = "worker"
 
 var Module = fx.Module(
 	ModuleName,
+	config.AsSchema[Settings](ModuleName),
 	fx.Provide(
 		ProvidePool,
 	),
 )
 
+type ProvideSQLLeaseStoreParams struct {
+	fx.In
+	Config *config.Config
+	DB     *sql.DB
+}
+
+// ProvideSQLLeaseStore provides the db lease store, to register with AsLeaseStore for the apps with singleton workers.
+func ProvideSQLLeaseStore(params ProvideSQLLeaseStoreParams) (*SQLLeaseStore, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSQLLeaseStore(params.DB, settings.Lease.Table), nil
+}
+
 type ProvidePoolParams struct {
 	fx.In
-	Logger  *slog.Logger
-	Workers []Worker `group:"worker-workers"`
+	Config        *config.Config
+	Logger        *slog.Logger
+	MeterProvider metric.MeterProvider
+	LeaseStore    LeaseStore           `optional:"true"`
+	Registrations []WorkerRegistration `group:"worker-workers-registrations"`
 }
 
-func ProvidePool(params ProvidePoolParams) *Pool {
-	return NewWorkerPool(params.Logger, params.Workers...)
+func ProvidePool(params ProvidePoolParams) (*Pool, error) {
+	settings, err := config.Bind[Settings](params.Config, ModuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Lease.Holder == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve lease holder: %w", err)
+		}
+
+		settings.Lease.Holder = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	registrations := params.Registrations
+
+	// singleton workers run while holding their lease, replaced in the registrations
+	for i, registration := range registrations {
+		if !newWorkerOptions(registration.Options...).singleton {
+			continue
+		}
+
+		if params.LeaseStore == nil {
+			return nil, errors.New("singleton workers require a lease store, see worker.AsLeaseStore")
+		}
+
+		registrations[i].Worker = NewSingletonWorker(params.Logger, registration.Worker, params.LeaseStore, settings.Lease)
+	}
+
+	return NewWorkerPool(
+		params.Logger,
+		params.MeterProvider.Meter("github.com/go-oryn/oryn-sandbox/pkg/worker"),
+		registrations...,
+	)
 }
 
-func RunWorkers() fx.Option {
+// RunWorkers runs the named workers, or all of them if no names are given.
+func RunWorkers(names ...string) fx.Option {
 	return fx.Invoke(
-		func(lifecycle fx.Lifecycle, pool *Pool) {
-			lifecycle.Append(fx.Hook{
+		func(lc fx.Lifecycle, manager *lifecycle.Manager, shutdown fx.Shutdowner, pool *Pool) error {
+			if len(names) > 0 {
+				if err := pool.Select(names...); err != nil {
+					return err
+				}
+			}
+
+			// a critical worker failure stops the app
+			pool.OnCriticalFailure(func(error) {
+				_ = shutdown.Shutdown(fx.ExitCode(1))
+			})
+
+			lc.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
 					return pool.Start(ctx)
 				},
-				OnStop: func(ctx context.Context) error {
-					return pool.Stop(ctx)
-				},
 			})
+
+			manager.OnStop(lifecycle.PhaseWorkers, "workers pool", pool.Stop)
+
+			return nil
 		},
 	)
 }
@@ -0,0 +1,92 @@
+package worker
+
+import "time"
+
+// RestartPolicy tells when a worker is restarted after its Run returns.
+type RestartPolicy string
+
+const (
+	// RestartNever never restarts the worker.
+	RestartNever RestartPolicy = "never"
+	// RestartOnFailure restarts the worker when it returns an error, or panics.
+	RestartOnFailure RestartPolicy = "on-failure"
+	// RestartAlways restarts the worker whenever it returns, until the pool stops.
+	RestartAlways RestartPolicy = "always"
+)
+
+const (
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+	DefaultJitter         = 0.2
+)
+
+type WorkerOption func(o *workerOptions)
+
+type workerOptions struct {
+	restartPolicy  RestartPolicy
+	maxRestarts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	critical       bool
+	singleton      bool
+}
+
+// newWorkerOptions applies the options, by default a worker is restarted on failure without limit,
+// with an exponential backoff from 1s to 1m and a 20% jitter.
+func newWorkerOptions(options ...WorkerOption) workerOptions {
+	o := workerOptions{
+		restartPolicy:  RestartOnFailure,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		jitter:         DefaultJitter,
+	}
+
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	return o
+}
+
+func WithRestartPolicy(policy RestartPolicy) WorkerOption {
+	return func(o *workerOptions) {
+		o.restartPolicy = policy
+	}
+}
+
+// WithMaxRestarts limits the number of restarts of the worker, 0 means unlimited.
+func WithMaxRestarts(maxRestarts int) WorkerOption {
+	return func(o *workerOptions) {
+		o.maxRestarts = maxRestarts
+	}
+}
+
+// WithBackoff sets the delay before the first restart, doubled on each restart up to the max delay.
+func WithBackoff(initial time.Duration, maxBackoff time.Duration) WorkerOption {
+	return func(o *workerOptions) {
+		o.initialBackoff = initial
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithJitter randomizes the restarts delays by +/- the jitter fraction, between 0 and 1.
+func WithJitter(jitter float64) WorkerOption {
+	return func(o *workerOptions) {
+		o.jitter = jitter
+	}
+}
+
+// Critical makes the final failure of the worker stop the whole pool, and its siblings.
+func Critical() WorkerOption {
+	return func(o *workerOptions) {
+		o.critical = true
+	}
+}
+
+// Singleton makes the worker run on the single replica holding its lease, see SingletonWorker.
+func Singleton() WorkerOption {
+	return func(o *workerOptions) {
+		o.singleton = true
+	}
+}
@@ -2,9 +2,14 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 
-	"golang.org/x/sync/errgroup"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type Worker interface {
@@ -12,42 +17,122 @@ type Worker interface {
 	Run(ctx context.Context) error
 }
 
+// WorkerRegistration is a worker registered in the Pool, with its options.
+type WorkerRegistration struct {
+	Worker  Worker
+	Options []WorkerOption
+}
+
+// Pool runs the workers independently, each one supervised according to its options.
 type Pool struct {
-	running      bool
-	logger       *slog.Logger
-	workers      []Worker
-	errGrp       *errgroup.Group
-	errGrpCancel context.CancelFunc
+	mu                sync.Mutex
+	running           bool
+	logger            *slog.Logger
+	metrics           *poolMetrics
+	supervisors       []*supervisor
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
+	onCriticalFailure func(err error)
+}
+
+// NewWorkerPool returns a Pool reporting its workers statuses as metrics with the meter.
+func NewWorkerPool(logger *slog.Logger, meter metric.Meter, registrations ...WorkerRegistration) (*Pool, error) {
+	metrics, err := newPoolMetrics(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	supervisors := make([]*supervisor, 0, len(registrations))
+	for _, registration := range registrations {
+		supervisors = append(supervisors, &supervisor{
+			logger:  logger,
+			worker:  registration.Worker,
+			options: newWorkerOptions(registration.Options...),
+			state:   WorkerStopped,
+		})
+	}
+
+	pool := &Pool{
+		logger:      logger,
+		metrics:     metrics,
+		supervisors: supervisors,
+	}
+
+	_, err = meter.RegisterCallback(pool.observe, metrics.state, metrics.restarts, metrics.uptime, metrics.leases)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// OnCriticalFailure sets a function called when a critical worker fails, after the pool is stopped.
+func (p *Pool) OnCriticalFailure(fn func(err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onCriticalFailure = fn
 }
 
-func NewWorkerPool(logger *slog.Logger, workers ...Worker) *Pool {
-	return &Pool{
-		logger:  logger,
-		workers: workers,
+// Select restricts the pool to the named workers, before its start.
+func (p *Pool) Select(names ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		return errors.New("cannot select the workers of a started pool")
+	}
+
+	var selected []*supervisor
+	for _, s := range p.supervisors {
+		if slices.Contains(names, s.worker.Name()) {
+			selected = append(selected, s)
+		}
+	}
+
+	if len(selected) != len(names) {
+		var available []string
+		for _, s := range p.supervisors {
+			available = append(available, s.worker.Name())
+		}
+
+		return fmt.Errorf("unknown workers in %s, available workers: %s", strings.Join(names, ", "), strings.Join(available, ", "))
 	}
+
+	p.supervisors = selected
+
+	return nil
 }
 
 func (p *Pool) Start(ctx context.Context) error {
-	errGrpCtx, errGrpCancel := context.WithCancel(context.Background())
-	errGrp, errGrpCtx := errgroup.WithContext(errGrpCtx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	p.errGrp = errGrp
-	p.errGrpCancel = errGrpCancel
+	if p.running {
+		return nil
+	}
+
+	poolCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	p.cancel = cancel
 
-	p.logger.DebugContext(errGrpCtx, "starting workers pool")
+	p.logger.DebugContext(poolCtx, "starting workers pool")
 
-	for _, w := range p.workers {
-		p.errGrp.Go(func() error {
-			p.logger.DebugContext(errGrpCtx, "starting worker", "worker", w.Name())
+	for _, s := range p.supervisors {
+		s.setState(WorkerStarting)
+	}
 
-			err := w.Run(errGrpCtx)
-			if err != nil {
-				p.logger.ErrorContext(errGrpCtx, "worker stopped with error", "worker", w.Name(), "error", err)
-			} else {
-				p.logger.DebugContext(errGrpCtx, "worker stopped with success", "worker", w.Name())
+	for _, s := range p.supervisors {
+		p.wg.Go(func() {
+			err := s.run(poolCtx)
+			if err == nil {
+				return
 			}
 
-			return err
+			p.logger.ErrorContext(poolCtx, "worker stopped with error", "worker", s.worker.Name(), "error", err)
+
+			if s.options.critical {
+				p.fail(err)
+			}
 		})
 	}
 
@@ -55,7 +140,7 @@ func (p *Pool) Start(ctx context.Context) error {
 
 	select {
 	case <-ctx.Done():
-		p.errGrpCancel()
+		cancel()
 		p.running = false
 
 		return ctx.Err()
@@ -64,8 +149,26 @@ func (p *Pool) Start(ctx context.Context) error {
 	}
 }
 
+// fail stops the pool on a critical worker failure.
+func (p *Pool) fail(err error) {
+	p.mu.Lock()
+	p.cancel()
+	p.running = false
+	onCriticalFailure := p.onCriticalFailure
+	p.mu.Unlock()
+
+	p.logger.Error("critical worker failed, stopping workers pool", "error", err)
+
+	if onCriticalFailure != nil {
+		onCriticalFailure(err)
+	}
+}
+
 func (p *Pool) Stop(ctx context.Context) error {
-	if p.errGrp == nil || p.running == false {
+	p.mu.Lock()
+	if p.cancel == nil {
+		p.mu.Unlock()
+
 		p.logger.WarnContext(ctx, "workers pool is not started")
 
 		return nil
@@ -73,27 +176,41 @@ func (p *Pool) Stop(ctx context.Context) error {
 
 	p.logger.DebugContext(ctx, "stopping workers pool")
 
-	if p.errGrpCancel != nil {
-		p.errGrpCancel()
-	}
+	p.cancel()
+	p.running = false
+	p.mu.Unlock()
 
-	done := make(chan error, 1)
+	done := make(chan struct{})
 	go func() {
-		done <- p.errGrp.Wait()
+		p.wg.Wait()
+		close(done)
 	}()
 
 	select {
-	case err := <-done:
-		p.running = false
-
-		return err
+	case <-done:
+		return nil
 	case <-ctx.Done():
-		p.running = false
-
 		return ctx.Err()
 	}
 }
 
+// Status returns the status of each worker, in their registration order.
+func (p *Pool) Status() []WorkerStatus {
+	p.mu.Lock()
+	supervisors := p.supervisors
+	p.mu.Unlock()
+
+	statuses := make([]WorkerStatus, 0, len(supervisors))
+	for _, s := range supervisors {
+		statuses = append(statuses, s.status())
+	}
+
+	return statuses
+}
+
 func (p *Pool) Running() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	return p.running
 }
@@ -0,0 +1,236 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type testWorker struct {
+	name string
+	runs atomic.Int32
+	run  func(ctx context.Context, runs int32) error
+}
+
+func (w *testWorker) Name() string {
+	return w.name
+}
+
+func (w *testWorker) Run(ctx context.Context) error {
+	return w.run(ctx, w.runs.Add(1))
+}
+
+func blocking(ctx context.Context, _ int32) error {
+	<-ctx.Done()
+
+	return nil
+}
+
+func newPool(t *testing.T, registrations ...worker.WorkerRegistration) *worker.Pool {
+	t.Helper()
+
+	pool, err := worker.NewWorkerPool(slog.New(slog.NewTextHandler(io.Discard, nil)), noop.NewMeterProvider().Meter("test"), registrations...)
+	require.NoError(t, err)
+
+	return pool
+}
+
+func TestPoolSupervision(t *testing.T) {
+	backoff := worker.WithBackoff(time.Millisecond, 5*time.Millisecond)
+
+	t.Run("restarts failing and panicking workers", func(t *testing.T) {
+		failing := &testWorker{name: "failing", run: func(ctx context.Context, runs int32) error {
+			if runs < 3 {
+				return errors.New("failure")
+			}
+
+			return blocking(ctx, runs)
+		}}
+
+		panicking := &testWorker{name: "panicking", run: func(ctx context.Context, runs int32) error {
+			if runs == 1 {
+				panic("boom")
+			}
+
+			return blocking(ctx, runs)
+		}}
+
+		pool := newPool(
+			t,
+			worker.WorkerRegistration{Worker: failing, Options: []worker.WorkerOption{backoff}},
+			worker.WorkerRegistration{Worker: panicking, Options: []worker.WorkerOption{backoff}},
+		)
+
+		require.NoError(t, pool.Start(context.Background()))
+
+		assert.Eventually(t, func() bool {
+			return failing.runs.Load() == 3 && panicking.runs.Load() == 2
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, pool.Stop(context.Background()))
+		assert.False(t, pool.Running())
+	})
+
+	t.Run("applies restart policies and max restarts", func(t *testing.T) {
+		never := &testWorker{name: "never", run: func(context.Context, int32) error {
+			return errors.New("failure")
+		}}
+
+		always := &testWorker{name: "always", run: func(context.Context, int32) error {
+			return nil
+		}}
+
+		limited := &testWorker{name: "limited", run: func(context.Context, int32) error {
+			return errors.New("failure")
+		}}
+
+		pool := newPool(
+			t,
+			worker.WorkerRegistration{Worker: never, Options: []worker.WorkerOption{backoff, worker.WithRestartPolicy(worker.RestartNever)}},
+			worker.WorkerRegistration{Worker: always, Options: []worker.WorkerOption{backoff, worker.WithRestartPolicy(worker.RestartAlways), worker.WithMaxRestarts(4)}},
+			worker.WorkerRegistration{Worker: limited, Options: []worker.WorkerOption{backoff, worker.WithMaxRestarts(2)}},
+		)
+
+		require.NoError(t, pool.Start(context.Background()))
+
+		assert.Eventually(t, func() bool {
+			return always.runs.Load() == 5 && limited.runs.Load() == 3
+		}, time.Second, time.Millisecond)
+
+		assert.Equal(t, int32(1), never.runs.Load())
+		assert.True(t, pool.Running())
+
+		require.NoError(t, pool.Stop(context.Background()))
+	})
+
+	t.Run("critical worker failure stops its siblings", func(t *testing.T) {
+		critical := &testWorker{name: "critical", run: func(context.Context, int32) error {
+			return errors.New("failure")
+		}}
+
+		sibling := &testWorker{name: "sibling", run: blocking}
+
+		pool := newPool(
+			t,
+			worker.WorkerRegistration{Worker: critical, Options: []worker.WorkerOption{backoff, worker.WithMaxRestarts(1), worker.Critical()}},
+			worker.WorkerRegistration{Worker: sibling},
+		)
+
+		failures := make(chan error, 1)
+		pool.OnCriticalFailure(func(err error) {
+			failures <- err
+		})
+
+		require.NoError(t, pool.Start(context.Background()))
+
+		select {
+		case err := <-failures:
+			assert.EqualError(t, err, "worker critical reached its 1 max restarts: failure")
+		case <-time.After(time.Second):
+			t.Fatal("expected a critical failure")
+		}
+
+		assert.False(t, pool.Running())
+		require.NoError(t, pool.Stop(context.Background()))
+	})
+}
+
+func TestPoolStatus(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+
+	failing := &testWorker{name: "failing", run: func(context.Context, int32) error {
+		return errors.New("failure")
+	}}
+
+	running := &testWorker{name: "running", run: blocking}
+
+	pool, err := worker.NewWorkerPool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"),
+		worker.WorkerRegistration{Worker: failing, Options: []worker.WorkerOption{
+			worker.WithBackoff(time.Millisecond, time.Millisecond),
+			worker.WithMaxRestarts(1),
+			worker.Critical(),
+		}},
+		worker.WorkerRegistration{Worker: running},
+	)
+	require.NoError(t, err)
+
+	probe := worker.NewWorkersProbe(pool)
+
+	assert.Equal(t, worker.WorkerStopped, pool.Status()[0].State)
+	assert.NoError(t, probe.Probe(ctx))
+
+	pool.OnCriticalFailure(func(error) {})
+	require.NoError(t, pool.Start(ctx))
+
+	assert.Eventually(t, func() bool {
+		return pool.Status()[0].State == worker.WorkerFailed
+	}, time.Second, time.Millisecond)
+
+	statuses := pool.Status()
+	assert.Equal(t, 1, statuses[0].Restarts)
+	assert.Equal(t, "worker failing reached its 1 max restarts: failure", statuses[0].LastError)
+	assert.Equal(t, "running", statuses[1].Name)
+	assert.EqualError(t, probe.Probe(ctx), "critical workers failed: failing")
+	assert.Equal(t, withoutUptime(statuses), withoutUptime(probe.Details().([]worker.WorkerStatus)))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	// one data point per worker and state
+	state := metrics["worker.state"].(metricdata.Gauge[int64])
+	assert.Len(t, state.DataPoints, 2*len(worker.WorkerStates))
+
+	restarts := metrics["worker.restarts"].(metricdata.Sum[int64])
+	assert.Len(t, restarts.DataPoints, 2)
+
+	require.NoError(t, pool.Stop(ctx))
+}
+
+// withoutUptime zeroes the statuses uptime, which differs between two snapshots of a running worker.
+func withoutUptime(statuses []worker.WorkerStatus) []worker.WorkerStatus {
+	for i := range statuses {
+		statuses[i].Uptime = 0
+	}
+
+	return statuses
+}
+
+func TestPoolSelect(t *testing.T) {
+	pool := newPool(
+		t,
+		worker.WorkerRegistration{Worker: &testWorker{name: "foo", run: blocking}},
+		worker.WorkerRegistration{Worker: &testWorker{name: "bar", run: blocking}},
+	)
+
+	err := pool.Select("foo", "baz")
+	assert.EqualError(t, err, "unknown workers in foo, baz, available workers: foo, bar")
+
+	require.NoError(t, pool.Select("bar"))
+	require.NoError(t, pool.Start(context.Background()))
+
+	statuses := pool.Status()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "bar", statuses[0].Name)
+
+	assert.Error(t, pool.Select("foo"))
+	require.NoError(t, pool.Stop(context.Background()))
+}
@@ -1,15 +1,61 @@
 package worker
 
 import (
+	"fmt"
+	"sync/atomic"
+
 	"go.uber.org/fx"
 )
 
-func AsWorker(constructor any) fx.Option {
+// workersCount numbers the workers registered with AsWorker, to provide each of them under its own name.
+var workersCount atomic.Int64
+
+// AsWorker registers a worker constructor, with options like its restart policy, for example
+// AsWorker(NewGreetWorker, worker.WithRestartPolicy(worker.RestartAlways), worker.Critical()).
+func AsWorker(constructor any, options ...WorkerOption) fx.Option {
+	name := fmt.Sprintf(`name:"worker-%d"`, workersCount.Add(1))
+
 	return fx.Provide(
 		fx.Annotate(
 			constructor,
 			fx.As(new(Worker)),
-			fx.ResultTags(`group:"worker-workers"`),
+			fx.ResultTags(name),
+		),
+		fx.Annotate(
+			func(w Worker) WorkerRegistration {
+				return WorkerRegistration{
+					Worker:  w,
+					Options: options,
+				}
+			},
+			fx.ParamTags(name),
+			fx.ResultTags(`group:"worker-workers-registrations"`),
+		),
+	)
+}
+
+// AsSingletonWorker registers a worker constructor like AsWorker, the worker runs on the single replica holding its lease.
+func AsSingletonWorker(constructor any, options ...WorkerOption) fx.Option {
+	return AsWorker(constructor, append(options, Singleton())...)
+}
+
+// AsLeaseStore registers the lease store constructor of the singleton workers, like
+// AsLeaseStore(worker.ProvideSQLLeaseStore). A pool without singleton workers does not need one.
+func AsLeaseStore(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.As(new(LeaseStore)),
+		),
+	)
+}
+
+// AsWorkerRegistrations registers a constructor of workers with their options, returning a []WorkerRegistration.
+func AsWorkerRegistrations(constructor any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			constructor,
+			fx.ResultTags(`group:"worker-workers-registrations,flatten"`),
 		),
 	)
 }
@@ -0,0 +1,86 @@
+package worker_test
+
+import (
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/config"
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestAsWorker(t *testing.T) {
+	var registrations []worker.WorkerRegistration
+
+	// workers of the same type, with their own options
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		worker.AsWorker(func() *testWorker {
+			return &testWorker{name: "foo", run: blocking}
+		}),
+		worker.AsWorker(func() *testWorker {
+			return &testWorker{name: "bar", run: blocking}
+		}, worker.Critical()),
+		fx.Invoke(fx.Annotate(
+			func(r []worker.WorkerRegistration) {
+				registrations = r
+			},
+			fx.ParamTags(`group:"worker-workers-registrations"`),
+		)),
+	)
+	app.RequireStart().RequireStop()
+
+	require.Len(t, registrations, 2)
+
+	pool := newPool(t, registrations...)
+
+	critical := map[string]bool{}
+	for _, status := range pool.Status() {
+		critical[status.Name] = status.Critical
+	}
+
+	assert.Equal(t, map[string]bool{"foo": false, "bar": true}, critical)
+}
+
+func TestAsLeaseStore(t *testing.T) {
+	newApp := func(options ...fx.Option) *fx.App {
+		cfg, err := config.NewConfig()
+		require.NoError(t, err)
+
+		return fx.New(
+			fx.NopLogger,
+			worker.Module,
+			fx.Supply(cfg, slog.New(slog.DiscardHandler)),
+			fx.Provide(func() metric.MeterProvider {
+				return noop.NewMeterProvider()
+			}),
+			fx.Invoke(func(*worker.Pool) {}),
+			fx.Options(options...),
+		)
+	}
+
+	// without singleton workers, the pool needs no lease store, nor db
+	assert.NoError(t, newApp(worker.AsWorker(func() *testWorker {
+		return &testWorker{name: "foo", run: blocking}
+	})).Err())
+
+	err := newApp(worker.AsSingletonWorker(func() *testWorker {
+		return &testWorker{name: "foo", run: blocking}
+	})).Err()
+	assert.ErrorContains(t, err, "singleton workers require a lease store, see worker.AsLeaseStore")
+
+	assert.NoError(t, newApp(
+		worker.AsSingletonWorker(func() *testWorker {
+			return &testWorker{name: "foo", run: blocking}
+		}),
+		worker.AsLeaseStore(worker.ProvideSQLLeaseStore),
+		fx.Supply(&sql.DB{}),
+	).Err())
+}
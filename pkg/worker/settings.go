@@ -0,0 +1,16 @@
+package worker
+
+import "time"
+
+type Settings struct {
+	Lease LeaseSettings `mapstructure:"lease"`
+}
+
+// LeaseSettings configures the singleton workers leases, renewed every renew interval for the ttl.
+// The holder identifies the replica, its host name and process id by default.
+type LeaseSettings struct {
+	Table         string        `mapstructure:"table" default:"worker_leases" validate:"required"`
+	Holder        string        `mapstructure:"holder"`
+	TTL           time.Duration `mapstructure:"ttl" default:"15s" validate:"gt=0"`
+	RenewInterval time.Duration `mapstructure:"renew_interval" default:"5s" validate:"gt=0,ltfield=TTL"`
+}
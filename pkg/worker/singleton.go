@@ -0,0 +1,165 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrLeaseLost is the cause of a singleton worker context cancellation when its lease could not be renewed.
+var ErrLeaseLost = errors.New("lease lost")
+
+// SingletonWorker runs a worker on the single replica holding its lease, the other replicas wait to take it over.
+type SingletonWorker struct {
+	logger   *slog.Logger
+	worker   Worker
+	store    LeaseStore
+	settings LeaseSettings
+	mu       sync.Mutex
+	lease    Lease
+}
+
+func NewSingletonWorker(logger *slog.Logger, worker Worker, store LeaseStore, settings LeaseSettings) *SingletonWorker {
+	return &SingletonWorker{
+		logger:   logger,
+		worker:   worker,
+		store:    store,
+		settings: settings,
+	}
+}
+
+func (w *SingletonWorker) Name() string {
+	return w.worker.Name()
+}
+
+// Lease returns the last known lease of the worker.
+func (w *SingletonWorker) Lease() Lease {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.lease
+}
+
+// Held returns true if this replica holds the lease.
+func (w *SingletonWorker) Held() bool {
+	lease := w.Lease()
+
+	return lease.Holder == w.settings.Holder && time.Now().Before(lease.ExpiresAt)
+}
+
+// Run waits for the lease, and runs the worker while holding it.
+func (w *SingletonWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.settings.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		held, err := w.acquire(ctx)
+		if err != nil {
+			return err
+		}
+
+		if held {
+			return w.lead(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SingletonWorker) acquire(ctx context.Context) (bool, error) {
+	lease, err := w.store.Acquire(ctx, w.worker.Name(), w.settings.Holder, w.settings.TTL)
+	if err != nil {
+		return false, err
+	}
+
+	w.mu.Lock()
+	previous := w.lease.Holder
+	w.lease = lease
+	w.mu.Unlock()
+
+	if previous != lease.Holder {
+		w.logger.InfoContext(ctx, "worker lease holder changed", "worker", w.worker.Name(), "holder", lease.Holder)
+	}
+
+	return lease.Holder == w.settings.Holder, nil
+}
+
+// lead runs the worker while renewing its lease, and releases it once done.
+func (w *SingletonWorker) lead(ctx context.Context) error {
+	leadCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		w.renew(leadCtx, cancel)
+	})
+
+	err := w.worker.Run(leadCtx)
+
+	cancel(nil)
+	wg.Wait()
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), w.settings.RenewInterval)
+	defer releaseCancel()
+
+	if releaseErr := w.store.Release(releaseCtx, w.worker.Name(), w.settings.Holder); releaseErr != nil {
+		w.logger.WarnContext(ctx, "failed to release worker lease", "worker", w.worker.Name(), "error", releaseErr)
+	}
+
+	w.mu.Lock()
+	w.lease = Lease{Name: w.worker.Name()}
+	w.mu.Unlock()
+
+	if ctx.Err() == nil && errors.Is(context.Cause(leadCtx), ErrLeaseLost) {
+		return fmt.Errorf("worker %s stopped: %w", w.worker.Name(), ErrLeaseLost)
+	}
+
+	return err
+}
+
+// renew renews the lease until the context is canceled, and cancels it if the lease is lost,
+// or could not be renewed before its expiration.
+func (w *SingletonWorker) renew(ctx context.Context, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(w.settings.RenewInterval)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		held, err := w.acquire(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil && held {
+			renewed = time.Now()
+
+			continue
+		}
+
+		if err != nil {
+			w.logger.WarnContext(ctx, "failed to renew worker lease", "worker", w.worker.Name(), "error", err)
+
+			if time.Since(renewed) < w.settings.TTL {
+				continue
+			}
+		}
+
+		cancel(ErrLeaseLost)
+
+		return
+	}
+}
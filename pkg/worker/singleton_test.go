@@ -0,0 +1,108 @@
+package worker_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-oryn/oryn-sandbox/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]worker.Lease
+}
+
+func (s *memoryLeaseStore) Acquire(_ context.Context, name string, holder string, ttl time.Duration) (worker.Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lease, ok := s.leases[name]
+	if !ok || lease.Holder == holder || time.Now().After(lease.ExpiresAt) {
+		lease = worker.Lease{Name: name, Holder: holder, ExpiresAt: time.Now().Add(ttl)}
+		s.leases[name] = lease
+	}
+
+	return lease, nil
+}
+
+func (s *memoryLeaseStore) Release(_ context.Context, name string, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.leases[name].Holder == holder {
+		delete(s.leases, name)
+	}
+
+	return nil
+}
+
+func TestSingletonWorker(t *testing.T) {
+	store := &memoryLeaseStore{leases: map[string]worker.Lease{}}
+
+	var running atomic.Int32
+
+	newReplica := func(holder string) (*worker.SingletonWorker, context.CancelFunc, chan error) {
+		w := worker.NewSingletonWorker(
+			slog.New(slog.NewTextHandler(io.Discard, nil)),
+			&testWorker{name: "singleton", run: func(ctx context.Context, runs int32) error {
+				running.Add(1)
+				defer running.Add(-1)
+
+				return blocking(ctx, runs)
+			}},
+			store,
+			worker.LeaseSettings{Holder: holder, TTL: 50 * time.Millisecond, RenewInterval: 5 * time.Millisecond},
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		go func() {
+			done <- w.Run(ctx)
+		}()
+
+		return w, cancel, done
+	}
+
+	first, cancelFirst, firstDone := newReplica("first")
+
+	assert.Eventually(t, first.Held, time.Second, time.Millisecond)
+
+	second, cancelSecond, secondDone := newReplica("second")
+	defer cancelSecond()
+
+	assert.Eventually(t, func() bool {
+		return second.Lease().Holder == "first"
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, int32(1), running.Load())
+	assert.False(t, second.Held())
+
+	// failover on release
+	cancelFirst()
+	require.NoError(t, <-firstDone)
+
+	assert.Eventually(t, second.Held, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), running.Load())
+
+	// lease lost on takeover
+	store.mu.Lock()
+	store.leases["singleton"] = worker.Lease{Name: "singleton", Holder: "third", ExpiresAt: time.Now().Add(time.Hour)}
+	store.mu.Unlock()
+
+	select {
+	case err := <-secondDone:
+		assert.ErrorIs(t, err, worker.ErrLeaseLost)
+	case <-time.After(time.Second):
+		t.Fatal("expected the lease to be lost")
+	}
+
+	assert.Equal(t, int32(0), running.Load())
+}
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WorkerState string
+
+const (
+	WorkerStarting   WorkerState = "starting"
+	WorkerRunning    WorkerState = "running"
+	WorkerBackingOff WorkerState = "backing-off"
+	WorkerStopped    WorkerState = "stopped"
+	WorkerFailed     WorkerState = "failed"
+)
+
+// WorkerStates lists all the workers states.
+var WorkerStates = []WorkerState{WorkerStarting, WorkerRunning, WorkerBackingOff, WorkerStopped, WorkerFailed}
+
+type WorkerStatus struct {
+	Name      string        `json:"name"`
+	State     WorkerState   `json:"state"`
+	Critical  bool          `json:"critical"`
+	Restarts  int           `json:"restarts"`
+	LastError string        `json:"last_error,omitempty"`
+	Uptime    time.Duration `json:"uptime"`
+	Lease     *LeaseStatus  `json:"lease,omitempty"`
+}
+
+// LeaseStatus is the lease status of a singleton worker.
+type LeaseStatus struct {
+	Holder    string    `json:"holder,omitempty"`
+	Held      bool      `json:"held"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// MarshalJSON reports the uptime as a duration string, like 1m30s.
+func (s WorkerStatus) MarshalJSON() ([]byte, error) {
+	type workerStatus WorkerStatus
+
+	return json.Marshal(struct {
+		workerStatus
+		Uptime string `json:"uptime"`
+	}{
+		workerStatus: workerStatus(s),
+		Uptime:       s.Uptime.Truncate(time.Millisecond).String(),
+	})
+}
@@ -0,0 +1,177 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// supervisor runs a worker, and restarts it according to its restart policy.
+type supervisor struct {
+	logger  *slog.Logger
+	worker  Worker
+	options workerOptions
+
+	mu        sync.Mutex
+	state     WorkerState
+	restarts  int
+	lastError error
+	startedAt time.Time
+}
+
+// status returns the worker status, its uptime is the duration of its current run.
+func (s *supervisor) status() WorkerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := WorkerStatus{
+		Name:     s.worker.Name(),
+		State:    s.state,
+		Critical: s.options.critical,
+		Restarts: s.restarts,
+	}
+
+	if s.lastError != nil {
+		status.LastError = s.lastError.Error()
+	}
+
+	if s.state == WorkerRunning {
+		status.Uptime = time.Since(s.startedAt)
+	}
+
+	if singleton, ok := s.worker.(*SingletonWorker); ok {
+		lease := singleton.Lease()
+
+		status.Lease = &LeaseStatus{
+			Holder:    lease.Holder,
+			Held:      singleton.Held(),
+			ExpiresAt: lease.ExpiresAt,
+		}
+	}
+
+	return status
+}
+
+func (s *supervisor) setState(state WorkerState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state
+	if state == WorkerRunning {
+		s.startedAt = time.Now()
+	}
+}
+
+// stopped records the end of a worker run, with the state to report until its restart.
+func (s *supervisor) stopped(state WorkerState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state
+	if err != nil {
+		s.lastError = err
+	}
+}
+
+// run returns nil when the worker stops with the context, or the worker error when it is not restarted anymore.
+func (s *supervisor) run(ctx context.Context) error {
+	name := s.worker.Name()
+
+	for restarts := 0; ; restarts++ {
+		s.logger.DebugContext(ctx, "starting worker", "worker", name, "restarts", restarts)
+
+		s.setState(WorkerRunning)
+
+		err := s.safeRun(ctx)
+		if ctx.Err() != nil {
+			s.stopped(WorkerStopped, err)
+			s.logger.DebugContext(ctx, "worker stopped", "worker", name)
+
+			return nil
+		}
+
+		switch s.options.restartPolicy {
+		case RestartAlways:
+		case RestartOnFailure:
+			if err == nil {
+				s.stopped(WorkerStopped, nil)
+				s.logger.DebugContext(ctx, "worker stopped with success", "worker", name)
+
+				return nil
+			}
+		default:
+			return s.final(err)
+		}
+
+		if s.options.maxRestarts > 0 && restarts >= s.options.maxRestarts {
+			if err == nil {
+				return s.final(nil)
+			}
+
+			return s.final(fmt.Errorf("worker %s reached its %d max restarts: %w", name, s.options.maxRestarts, err))
+		}
+
+		delay := s.backoff(restarts)
+
+		s.stopped(WorkerBackingOff, err)
+		s.logger.WarnContext(ctx, "restarting worker", "worker", name, "error", err, "delay", delay, "restarts", restarts+1)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			s.stopped(WorkerStopped, nil)
+
+			return nil
+		}
+
+		s.mu.Lock()
+		s.restarts++
+		s.mu.Unlock()
+	}
+}
+
+// final records the end of the worker supervision, failed on error.
+func (s *supervisor) final(err error) error {
+	if err != nil {
+		s.stopped(WorkerFailed, err)
+	} else {
+		s.stopped(WorkerStopped, nil)
+	}
+
+	return err
+}
+
+// safeRun runs the worker, and turns its panics into errors with their stack trace.
+func (s *supervisor) safeRun(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("worker %s panicked: %v\n%s", s.worker.Name(), r, debug.Stack())
+		}
+	}()
+
+	return s.worker.Run(ctx)
+}
+
+// backoff returns the exponential delay before a restart, capped to the max backoff and randomized by the jitter.
+func (s *supervisor) backoff(restarts int) time.Duration {
+	delay := s.options.initialBackoff
+	for range restarts {
+		if delay >= s.options.maxBackoff {
+			break
+		}
+
+		delay *= 2
+	}
+
+	delay = min(delay, s.options.maxBackoff)
+
+	if s.options.jitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + s.options.jitter*(2*rand.Float64()-1)))
+	}
+
+	return delay
+}